@@ -5,67 +5,144 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-const binanceBaseURL = "https://api.binance.com"
+// Binance endpoints; TESTNET=true sends everything to the spot testnet instead
+const (
+	binanceLiveURL    = "https://api.binance.com"
+	binanceTestnetURL = "https://testnet.binance.vision"
+)
 
-// Place order on Binance
-func placeOrder(client *BinanceClient, symbol, side, orderType, quantity, price string) (string, error) {
-	endpoint := "/api/v3/order"
+var (
+	testnetOnce sync.Once
+	testnet     bool
+)
 
-	params := url.Values{}
-	params.Set("symbol", symbol)
-	params.Set("side", side)
-	params.Set("type", orderType)
-	params.Set("quantity", quantity)
-	params.Set("timeInForce", "GTC")
-	params.Set("timestamp", fmt.Sprintf("%d", time.Now().UnixNano()/1e6))
+// useTestnet reports whether TESTNET routes requests and orders to the spot testnet;
+// read once so a bad value is reported once
+func useTestnet() bool {
+	testnetOnce.Do(func() {
+		testnet = envBool("TESTNET", false)
+	})
+	return testnet
+}
 
-	if orderType == "LIMIT" {
-		params.Set("price", price)
+// binanceBaseURL returns the REST base for the live exchange or the testnet
+func binanceBaseURL() string {
+	if useTestnet() {
+		return binanceTestnetURL
 	}
+	return binanceLiveURL
+}
 
-	// Sign the request
-	signature := createSignature(params.Encode(), client.SecretKey)
-	params.Set("signature", signature)
+// Place order on Binance; the tag records which strategy owns it
+func placeOrder(client *BinanceClient, tag OrderTag, symbol, side, orderType, quantity, price string) (string, error) {
+	// Every order must pass the risk manager first
+	if err := checkOrderRisk(client, tag, symbol, side, orderType, quantity, price); err != nil {
+		return "", err
+	}
 
-	reqURL := binanceBaseURL + endpoint + "?" + params.Encode()
+	// Market orders are checked against the live book; a protected order is an IOC limit at the worst acceptable price
+	timeInForce := "GTC"
+	var slippage *SlippageEstimate
+	if orderType == "MARKET" {
+		var slippageErr error
+		slippage, slippageErr = checkSlippage(symbol, side, quantity, tag.Closing)
+		if slippage != nil && slippage.Action == "REFUSED" {
+			if entry, sent, err := journalBegin(tag, symbol, side, orderType, quantity, price, slippage); err == nil && !sent {
+				journalRecord(entry.ClientOrderID, "", "REFUSED", slippageErr.Error())
+			}
+		}
+		if slippageErr != nil {
+			return "", slippageErr
+		}
+		if slippage != nil && slippage.Action == "LIMIT" {
+			rules, err := getSymbolRules(symbol)
+			if err != nil {
+				return "", err
+			}
+			orderType, price, timeInForce = "LIMIT", rules.formatPrice(slippage.LimitPrice), "IOC"
+			fmt.Printf("🛡️ แปลงเป็น LIMIT IOC @ $%s\n", price)
+		}
+	}
 
-	req, err := http.NewRequest("POST", reqURL, nil)
+	// Journal the order before sending so a crash or retry can never duplicate it
+	entry, sent, err := journalBegin(tag, symbol, side, orderType, quantity, price, slippage)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("ไม่สามารถบันทึก order journal: %v", err)
+	}
+	if sent {
+		// The same decision was sent before: only send again when Binance never received it
+		existing, lookupErr := getOrderByClientID(client, symbol, entry.ClientOrderID)
+		if lookupErr == nil {
+			orderID := strconv.FormatInt(existing.OrderID, 10)
+			journalRecord(entry.ClientOrderID, orderID, existing.Status, "")
+			return orderID, nil
+		}
+		if !isOrderNotFound(lookupErr) {
+			return "", fmt.Errorf("ไม่ทราบสถานะ order %s ที่เคยส่ง: %v", entry.ClientOrderID, lookupErr)
+		}
 	}
 
-	req.Header.Set("X-MBX-APIKEY", client.APIKey)
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("side", side)
+	params.Set("type", orderType)
+	params.Set("quantity", quantity)
+	params.Set("newClientOrderId", entry.ClientOrderID)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
+	if orderType == "LIMIT" {
+		params.Set("timeInForce", timeInForce)
+		params.Set("price", price)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := signedRequest(client, "POST", "/api/v3/order", params)
+
+	var apiErr *APIError
+	if err != nil && !errors.As(err, &apiErr) {
+		// The request may have reached Binance before the connection failed
+		existing, lookupErr := getOrderByClientID(client, symbol, entry.ClientOrderID)
+		switch {
+		case lookupErr == nil:
+			orderID := strconv.FormatInt(existing.OrderID, 10)
+			journalRecord(entry.ClientOrderID, orderID, existing.Status, "")
+			return orderID, nil
+		case isOrderNotFound(lookupErr):
+			// Binance never received it, so sending again cannot fill twice
+			body, err = signedRequest(client, "POST", "/api/v3/order", params)
+		default:
+			// A filled order is no longer open, so a blind resend could execute twice; reconcile resolves it
+			journalRecord(entry.ClientOrderID, "", "UNKNOWN", err.Error())
+			return "", fmt.Errorf("ไม่ทราบสถานะ order %s (รอ reconcile): %v", entry.ClientOrderID, err)
+		}
+	}
 	if err != nil {
+		status := "FAILED"
+		if !errors.As(err, &apiErr) {
+			status = "UNKNOWN"
+		}
+		journalRecord(entry.ClientOrderID, "", status, err.Error())
 		return "", err
 	}
 
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("binance API error: %s", string(body))
-	}
-
-	var orderResponse map[string]interface{}
+	var orderResponse OrderStatus
 	if err := json.Unmarshal(body, &orderResponse); err != nil {
+		journalRecord(entry.ClientOrderID, "", "UNKNOWN", err.Error())
 		return "", err
 	}
 
-	orderID := fmt.Sprintf("%.0f", orderResponse["orderId"].(float64))
+	orderID := strconv.FormatInt(orderResponse.OrderID, 10)
+	journalRecord(entry.ClientOrderID, orderID, orderResponse.Status, "")
 	return orderID, nil
 }
 
@@ -76,10 +153,13 @@ func getBalances(client *BinanceClient) (map[string]float64, error) {
 	params := url.Values{}
 	params.Set("timestamp", fmt.Sprintf("%d", time.Now().UnixNano()/1e6))
 
-	signature := createSignature(params.Encode(), client.SecretKey)
+	signature, err := client.sign(params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถเซ็น request: %v", err)
+	}
 	params.Set("signature", signature)
 
-	reqURL := binanceBaseURL + endpoint + "?" + params.Encode()
+	reqURL := binanceBaseURL() + endpoint + "?" + params.Encode()
 
 	req, err := http.NewRequest("GET", reqURL, nil)
 	if err != nil {
@@ -128,9 +208,124 @@ func getBalances(client *BinanceClient) (map[string]float64, error) {
 	return balances, nil
 }
 
+// Get free and locked amounts for every asset with a non-zero balance
+func getAccountBalances(client *BinanceClient) ([]AssetBalance, error) {
+	body, err := signedRequest(client, "GET", "/api/v3/account", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var accountInfo struct {
+		Balances []struct {
+			Asset  string `json:"asset"`
+			Free   string `json:"free"`
+			Locked string `json:"locked"`
+		} `json:"balances"`
+	}
+
+	if err := json.Unmarshal(body, &accountInfo); err != nil {
+		return nil, err
+	}
+
+	var balances []AssetBalance
+	for _, balance := range accountInfo.Balances {
+		free, err1 := strconv.ParseFloat(balance.Free, 64)
+		locked, err2 := strconv.ParseFloat(balance.Locked, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if free > 0 || locked > 0 {
+			balances = append(balances, AssetBalance{Asset: balance.Asset, Free: free, Locked: locked})
+		}
+	}
+
+	return balances, nil
+}
+
+// Get the account's maker and taker commission rates (fractions, e.g. 0.001 = 0.1%)
+func getCommissionRates(client *BinanceClient) (maker, taker float64, err error) {
+	body, err := signedRequest(client, "GET", "/api/v3/account", nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var accountInfo struct {
+		CommissionRates struct {
+			Maker string `json:"maker"`
+			Taker string `json:"taker"`
+		} `json:"commissionRates"`
+	}
+	if err := json.Unmarshal(body, &accountInfo); err != nil {
+		return 0, 0, err
+	}
+
+	maker, err = strconv.ParseFloat(accountInfo.CommissionRates.Maker, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("maker commission ไม่ถูกต้อง: %q", accountInfo.CommissionRates.Maker)
+	}
+	taker, err = strconv.ParseFloat(accountInfo.CommissionRates.Taker, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("taker commission ไม่ถูกต้อง: %q", accountInfo.CommissionRates.Taker)
+	}
+	return maker, taker, nil
+}
+
+// Get whether spot fees are paid with BNB at a discount
+func getBNBBurn(client *BinanceClient) (bool, error) {
+	body, err := signedRequest(client, "GET", "/sapi/v1/bnbBurn", nil)
+	if err != nil {
+		return false, err
+	}
+
+	var burn struct {
+		SpotBNBBurn bool `json:"spotBNBBurn"`
+	}
+	if err := json.Unmarshal(body, &burn); err != nil {
+		return false, err
+	}
+	return burn.SpotBNBBurn, nil
+}
+
+// Get the latest price of every symbol in one request
+func getAllPrices() (map[string]float64, error) {
+	resp, err := http.Get(binanceBaseURL() + "/api/v3/ticker/price")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("binance API error: %s", string(body))
+	}
+
+	var priceResponses []struct {
+		Symbol string `json:"symbol"`
+		Price  string `json:"price"`
+	}
+	if err := json.Unmarshal(body, &priceResponses); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(priceResponses))
+	for _, p := range priceResponses {
+		price, err := strconv.ParseFloat(p.Price, 64)
+		if err != nil {
+			continue
+		}
+		prices[p.Symbol] = price
+	}
+
+	return prices, nil
+}
+
 // Get current price for specific symbol
 func getCurrentPriceForSymbol(client *BinanceClient, symbol string) (float64, error) {
-	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s", binanceBaseURL, symbol)
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s", binanceBaseURL(), symbol)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -169,9 +364,49 @@ func getCurrentPriceForSymbol(client *BinanceClient, symbol string) (float64, er
 	return price, nil
 }
 
+// Get an order book snapshot with up to limit levels per side
+func getOrderBook(symbol string, limit int) (*OrderBook, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/api/v3/depth?symbol=%s&limit=%d", binanceBaseURL(), symbol, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("binance API error: %s", string(body))
+	}
+
+	var depth struct {
+		Bids [][2]string `json:"bids"`
+		Asks [][2]string `json:"asks"`
+	}
+	if err := json.Unmarshal(body, &depth); err != nil {
+		return nil, err
+	}
+
+	parse := func(raw [][2]string) []BookLevel {
+		levels := make([]BookLevel, 0, len(raw))
+		for _, level := range raw {
+			price, err1 := strconv.ParseFloat(level[0], 64)
+			quantity, err2 := strconv.ParseFloat(level[1], 64)
+			if err1 == nil && err2 == nil {
+				levels = append(levels, BookLevel{Price: price, Quantity: quantity})
+			}
+		}
+		return levels
+	}
+
+	return &OrderBook{Bids: parse(depth.Bids), Asks: parse(depth.Asks)}, nil
+}
+
 // Get 24hr ticker statistics for all symbols
 func get24hrTickers() ([]Ticker24hr, error) {
-	url := fmt.Sprintf("%s/api/v3/ticker/24hr", binanceBaseURL)
+	url := fmt.Sprintf("%s/api/v3/ticker/24hr", binanceBaseURL())
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -201,101 +436,317 @@ func get24hrTickers() ([]Ticker24hr, error) {
 	return tickers, nil
 }
 
-// Cancel all open orders for a symbol
-func cancelAllOrders(client *BinanceClient, symbol string) error {
-	// Get all open orders first
-	endpoint := "/api/v3/openOrders"
-
+// Cancel all open orders for a symbol in one request and return the cancelled orders
+func cancelAllOrders(client *BinanceClient, symbol string) ([]OrderStatus, error) {
 	params := url.Values{}
 	params.Set("symbol", symbol)
+
+	body, err := signedRequest(client, "DELETE", "/api/v3/openOrders", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var canceled []OrderStatus
+	if err := json.Unmarshal(body, &canceled); err != nil {
+		return nil, err
+	}
+	return canceled, nil
+}
+
+// APIError represents a request that Binance received and answered with an error
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("binance API error: %s", e.Body)
+}
+
+// Send a signed request to an authenticated endpoint and return the response body
+func signedRequest(client *BinanceClient, method, endpoint string, params url.Values) ([]byte, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	// Params may be reused for a retry, so never sign an old signature
+	params.Del("signature")
 	params.Set("timestamp", fmt.Sprintf("%d", time.Now().UnixNano()/1e6))
 
-	signature := createSignature(params.Encode(), client.SecretKey)
+	signature, err := client.sign(params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถเซ็น request: %v", err)
+	}
 	params.Set("signature", signature)
 
-	reqURL := binanceBaseURL + endpoint + "?" + params.Encode()
+	reqURL := binanceBaseURL() + endpoint + "?" + params.Encode()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequest(method, reqURL, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("X-MBX-APIKEY", client.APIKey)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("binance API error getting orders: %s", string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var orders []map[string]interface{}
-	if err := json.Unmarshal(body, &orders); err != nil {
-		return err
+	return body, nil
+}
+
+// Send a request that needs only the API key header (user data stream endpoints)
+func apiKeyRequest(client *BinanceClient, method, endpoint string, params url.Values) ([]byte, error) {
+	reqURL := binanceBaseURL() + endpoint
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequest(method, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-MBX-APIKEY", client.APIKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return body, nil
+}
+
+// Get a single order by its exchange order ID
+func getOrder(client *BinanceClient, symbol, orderID string) (*OrderStatus, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+
+	body, err := signedRequest(client, "GET", "/api/v3/order", params)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(orders) == 0 {
-		fmt.Printf("✅ ไม่มี orders ที่ต้องยกเลิกสำหรับ %s\n", symbol)
-		return nil
+	var order OrderStatus
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, err
 	}
+	return &order, nil
+}
 
-	fmt.Printf("🗑️ กำลังยกเลิก %d orders สำหรับ %s...\n", len(orders), symbol)
+// Get a single order by the client order ID it was placed with
+func getOrderByClientID(client *BinanceClient, symbol, clientOrderID string) (*OrderStatus, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("origClientOrderId", clientOrderID)
 
-	// Cancel all orders
-	canceledCount := 0
-	for _, order := range orders {
-		orderID := fmt.Sprintf("%.0f", order["orderId"].(float64))
+	body, err := signedRequest(client, "GET", "/api/v3/order", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var order OrderStatus
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
 
-		params := url.Values{}
+// Get open orders for a symbol, or for every symbol when symbol is empty
+func getOpenOrders(client *BinanceClient, symbol string) ([]OrderStatus, error) {
+	params := url.Values{}
+	if symbol != "" {
 		params.Set("symbol", symbol)
-		params.Set("orderId", orderID)
-		params.Set("timestamp", fmt.Sprintf("%d", time.Now().UnixNano()/1e6))
+	}
 
-		signature := createSignature(params.Encode(), client.SecretKey)
-		params.Set("signature", signature)
+	body, err := signedRequest(client, "GET", "/api/v3/openOrders", params)
+	if err != nil {
+		return nil, err
+	}
 
-		reqURL := binanceBaseURL + "/api/v3/order?" + params.Encode()
+	var orders []OrderStatus
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
 
-		req, err := http.NewRequest("DELETE", reqURL, nil)
-		if err != nil {
-			fmt.Printf("❌ ไม่สามารถสร้าง request ยกเลิก order %s: %v\n", orderID, err)
-			continue
-		}
+// Get the account's trades for a symbol starting at trade ID fromID
+func getMyTrades(client *BinanceClient, symbol string, fromID int64) ([]Trade, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("fromId", strconv.FormatInt(fromID, 10))
+	params.Set("limit", "1000")
 
-		req.Header.Set("X-MBX-APIKEY", client.APIKey)
+	body, err := signedRequest(client, "GET", "/api/v3/myTrades", params)
+	if err != nil {
+		return nil, err
+	}
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			fmt.Printf("❌ ไม่สามารถยกเลิก order %s: %v\n", orderID, err)
-			continue
-		}
-		resp.Body.Close()
+	var trades []Trade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
 
-		if resp.StatusCode == 200 {
-			canceledCount++
-			fmt.Printf("✅ ยกเลิก order %s สำเร็จ\n", orderID)
-		} else {
-			fmt.Printf("❌ ไม่สามารถยกเลิก order %s (status: %d)\n", orderID, resp.StatusCode)
+// Get the account's trades for a symbol executed at or after startTime
+func getMyTradesSince(client *BinanceClient, symbol string, startTime time.Time) ([]Trade, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	params.Set("limit", "1000")
+
+	body, err := signedRequest(client, "GET", "/api/v3/myTrades", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []Trade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// Get the account's most recent trade for a symbol, nil when it never traded it
+func getLatestTrade(client *BinanceClient, symbol string) (*Trade, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("limit", "1")
+
+	body, err := signedRequest(client, "GET", "/api/v3/myTrades", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []Trade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, err
+	}
+	if len(trades) == 0 {
+		return nil, nil
+	}
+	return &trades[len(trades)-1], nil
+}
+
+// Cancel a single order by its exchange order ID
+func cancelOrder(client *BinanceClient, symbol, orderID string) error {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+
+	_, err := signedRequest(client, "DELETE", "/api/v3/order", params)
+	return err
+}
+
+var (
+	symbolRulesMu    sync.Mutex
+	symbolRulesCache = map[string]*SymbolRules{}
+)
+
+// Get price/quantity rules for a symbol (cached for the lifetime of the process)
+func getSymbolRules(symbol string) (*SymbolRules, error) {
+	symbolRulesMu.Lock()
+	defer symbolRulesMu.Unlock()
+
+	if rules, ok := symbolRulesCache[symbol]; ok {
+		return rules, nil
+	}
+
+	resp, err := http.Get(binanceBaseURL() + "/api/v3/exchangeInfo?symbol=" + symbol)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching exchange info: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("binance API error: %s", string(body))
+	}
+
+	var info ExchangeInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("error unmarshaling exchange info: %v", err)
+	}
+	if len(info.Symbols) == 0 {
+		return nil, fmt.Errorf("ไม่พบสัญลักษณ์ %s", symbol)
+	}
+
+	rules := &SymbolRules{Symbol: symbol}
+	for _, filter := range info.Symbols[0].Filters {
+		switch filter.FilterType {
+		case "PRICE_FILTER":
+			rules.TickSize, _ = strconv.ParseFloat(filter.TickSize, 64)
+			rules.tickDecimals = stepDecimals(filter.TickSize)
+		case "LOT_SIZE":
+			rules.StepSize, _ = strconv.ParseFloat(filter.StepSize, 64)
+			rules.MinQty, _ = strconv.ParseFloat(filter.MinQty, 64)
+			rules.stepDecimals = stepDecimals(filter.StepSize)
+		case "NOTIONAL", "MIN_NOTIONAL":
+			rules.MinNotional, _ = strconv.ParseFloat(filter.MinNotional, 64)
 		}
+	}
+
+	symbolRulesCache[symbol] = rules
+	return rules, nil
+}
 
-		time.Sleep(100 * time.Millisecond) // Rate limiting
+// Number of decimals in a step string such as "0.00100000"
+func stepDecimals(step string) int {
+	dot := strings.Index(step, ".")
+	if dot < 0 {
+		return 0
 	}
+	return len(strings.TrimRight(step[dot+1:], "0"))
+}
+
+// Round down to a multiple of step
+func floorToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return math.Floor(value/step+1e-9) * step
+}
 
-	fmt.Printf("🎯 ยกเลิกสำเร็จ %d/%d orders สำหรับ %s\n", canceledCount, len(orders), symbol)
-	return nil
+// Format a price that is valid for the symbol's tick size
+func (r *SymbolRules) formatPrice(price float64) string {
+	return strconv.FormatFloat(floorToStep(price, r.TickSize), 'f', r.tickDecimals, 64)
+}
+
+// Format a quantity that is valid for the symbol's lot size
+func (r *SymbolRules) formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(floorToStep(quantity, r.StepSize), 'f', r.stepDecimals, 64)
 }
 
 // Get exchange info to check listing dates
 func getExchangeInfo() (*ExchangeInfo, error) {
-	url := binanceBaseURL + "/api/v3/exchangeInfo"
+	url := binanceBaseURL() + "/api/v3/exchangeInfo"
 
 	resp, err := http.Get(url)
 	if err != nil {
@@ -389,12 +840,29 @@ func isNewCoinMonthly(symbol string) bool {
 	return activeMonths <= 2
 }
 
-// Get accurate coin age using daily data (Step 2: 144 days history)
+// getListingTime returns when a symbol first traded, from its earliest 1h kline
+func getListingTime(symbol string) (time.Time, error) {
+	klines, err := getKlinesFrom(&BinanceClient{}, symbol, "1h", 0, 1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(klines) == 0 {
+		return time.Time{}, fmt.Errorf("ไม่พบข้อมูลการเทรด %s", symbol)
+	}
+	return time.UnixMilli(klines[0].OpenTime), nil
+}
+
+// Get accurate coin age from the listing time: the first 24 hours are day 1
 func getCoinAgeDaysDetailed(symbol string) int {
+	listed, err := getListingTime(symbol)
+	if err == nil {
+		return int(time.Since(listed).Hours()/24) + 1
+	}
+
 	// Create dummy client for kline requests (public endpoint)
 	client := &BinanceClient{}
 
-	// Get 144 days of daily data for detailed analysis
+	// Fall back to counting active days in 144 days of daily data
 	dailyKlines, err := getKlines(client, symbol, "1d", 144)
 	if err != nil {
 		// Fallback to pattern-based estimation if API fails
@@ -495,35 +963,52 @@ func analyzeNewCoinsWithAI(coins []CoinInfo) ([]AINewCoinAnalysis, error) {
 
 	// Create dummy client for public API calls (no auth needed for klines)
 	client := &BinanceClient{}
+	fees := currentFeeSchedule()
+	timeframes, baseTimeframe := loadTimeframes(), loadBaseTimeframe()
+	recoveryConfig := loadRecoveryConfig()
+	profileConfig := loadVolumeProfileConfig()
 
 	for i, coin := range coins {
 		if i%3 == 0 {
 			fmt.Printf("   AI วิเคราะห์แล้ว %d/%d เหรียญ...\n", i, len(coins))
 		}
 
-		// Get daily klines for detailed analysis (144 days)
-		klines, err := getKlines(client, coin.Symbol, "1d", 144)
+		// History since listing feeds the recovery detector and the volume profile;
+		// the scan's recovery measurement is reused when it made one
+		if history, historyInterval, err := getListingHistory(coin.Symbol, coin.AgeDays); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+		} else {
+			if coin.Recovery == nil {
+				recovery := detectRecovery(history, historyInterval, recoveryConfig, time.Now())
+				coin.Recovery = &recovery
+			}
+			coin.Profile = buildVolumeProfile(history, historyInterval, profileConfig)
+		}
+
+		// Analyze every TIMEFRAME interval with AI-like logic
+		analysis, err := analyzeCoinTimeframes(client, coin, timeframes, baseTimeframe)
 		if err != nil {
-			fmt.Printf("⚠️ ไม่สามารถดึงข้อมูล %s: %v\n", coin.Symbol, err)
+			fmt.Printf("⚠️ %v\n", err)
 			continue
 		}
+		applyFeeTargets(analysis, fees)
 
-		if len(klines) < 30 {
-			fmt.Printf("⚠️ ข้อมูล %s ไม่เพียงพอสำหรับวิเคราะห์\n", coin.Symbol)
-			continue
+		// Post-listing dump and recovery
+		if coin.Recovery != nil {
+			analysis.Recovery = coin.Recovery
+			analysis.TechnicalSummary += ", " + coin.Recovery.summary()
 		}
 
-		// Analyze with AI-like logic
-		analysis := performAIAnalysis(coin, klines)
-		analyses = append(analyses, analysis)
+		analyses = append(analyses, *analysis)
 	}
 
 	fmt.Printf("✅ AI วิเคราะห์เสร็จสิ้น: %d เหรียญ\n", len(analyses))
 	return analyses, nil
 }
 
-// performAIAnalysis performs AI-like technical analysis focused on daily market structure
-func performAIAnalysis(coin CoinInfo, klines []Kline) AINewCoinAnalysis {
+// performAIAnalysis performs AI-like technical analysis of market structure on one interval's klines;
+// "daily" and "weekly" windows are 30 and 7 candles of that interval
+func performAIAnalysis(coin CoinInfo, klines []Kline, interval string) AINewCoinAnalysis {
 	// Calculate technical indicators for daily timeframe analysis
 	prices := make([]float64, len(klines))
 	volumes := make([]float64, len(klines))
@@ -537,38 +1022,50 @@ func performAIAnalysis(coin CoinInfo, klines []Kline) AINewCoinAnalysis {
 		lows[i] = k.Low
 	}
 
+	// Windows and indicator periods shrink to fit short histories of fresh listings
+	windows := scaleAnalysisWindows(len(klines))
+
+	// Indicator library, periods from .env (RSI_PERIOD, SMA_SHORT, SMA_LONG, MACD_*)
+	indicators := computeIndicators(klines, scaleIndicatorConfig(loadIndicatorConfig(), len(klines)))
+
 	// Daily Market Structure Analysis
-	// Short-term daily structure (7, 14, 21 days)
-	ma7 := calculateSMA(prices, 7)
-	ma14 := calculateSMA(prices, 14)
-	ma21 := calculateSMA(prices, 21)
+	// Medium-term daily structure (14, 21 days)
+	ma14 := calculateSMA(prices, windows.MediumMA)
+	ma21 := calculateSMA(prices, windows.LongMA)
 
 	// Medium-term daily structure (30 days for new coins)
-	ma30 := calculateSMA(prices, 30)
+	ma30 := calculateSMA(prices, windows.TrendMA)
 
 	// Current market state based on daily structure
 	currentPrice := prices[len(prices)-1]
 
 	// Daily structure levels (30-day period for new coins)
-	dailyPeriod := min(30, len(prices))
+	dailyPeriod := min(windows.Structure, len(prices))
 	recentDailyHigh := findMax(highs[len(highs)-dailyPeriod:])
 	recentDailyLow := findMin(lows[len(lows)-dailyPeriod:])
 
 	// Weekly structure levels (7-day period)
-	weeklyPeriod := min(7, len(prices))
+	weeklyPeriod := min(windows.Swing, len(prices))
 	weeklyHigh := findMax(highs[len(highs)-weeklyPeriod:])
 	weeklyLow := findMin(lows[len(lows)-weeklyPeriod:])
 
 	// Volume profile for daily analysis
-	avgDailyVolume := calculateAverage(volumes[max(0, len(volumes)-21):]) // 21-day avg
+	avgDailyVolume := calculateAverage(volumes[max(0, len(volumes)-windows.Volume):]) // 21-day avg
 	recentDailyVolume := volumes[len(volumes)-1]
 	volumeTrend := recentDailyVolume > avgDailyVolume*1.2
 
 	// Daily trend analysis
-	shortTermTrend := len(ma7) > 0 && len(ma14) > 0 && ma7[len(ma7)-1] > ma14[len(ma14)-1]
+	shortTermTrend := indicators.SMAShort > 0 && indicators.SMALong > 0 && indicators.SMAShort > indicators.SMALong
 	mediumTermTrend := len(ma14) > 0 && len(ma21) > 0 && ma14[len(ma14)-1] > ma21[len(ma21)-1]
 	longTermTrend := len(ma21) > 0 && len(ma30) > 0 && ma21[len(ma21)-1] > ma30[len(ma30)-1]
 
+	trend := trendSideways
+	if shortTermTrend && mediumTermTrend {
+		trend = trendUp
+	} else if !shortTermTrend && !mediumTermTrend {
+		trend = trendDown
+	}
+
 	// Daily market structure breaks
 	isDailyBreakout := currentPrice >= recentDailyHigh*0.98 // Near daily high breakout
 	isDailySupport := currentPrice <= recentDailyLow*1.02   // Near daily low support
@@ -603,21 +1100,61 @@ func performAIAnalysis(coin CoinInfo, klines []Kline) AINewCoinAnalysis {
 		recommendedAction = "สะสม"
 	}
 
+	// Indicator confirmation: never chase an overbought coin, trust accumulation backed by momentum and OBV
+	if shouldAccumulate && indicators.Overbought {
+		shouldAccumulate = false
+		confidence = "ต่ำ"
+		recommendedAction = "รอ"
+	} else if shouldAccumulate && confidence == "ปานกลาง" && indicators.MomentumUp && indicators.OBVRising {
+		confidence = "สูง"
+	}
+
 	// Enhanced reverse signal detection using daily structure
 	reverseSignal := false
 	if isDailySupport && coin.PriceChange < -10 && volumeTrend {
 		reverseSignal = true
 	} else if isWeeklySupport && coin.PriceChange < -15 && volumeTrend {
 		reverseSignal = true
+	} else if indicators.Oversold && indicators.StochRSIK > indicators.StochRSID && indicators.OBVRising {
+		// Stochastic RSI turning up from oversold while buyers accumulate
+		reverseSignal = true
 	}
 
-	// Price targets based on daily structure levels
+	// Price targets: stop and targets scaled by volatility around the structure levels
 	accumulationRange := []float64{recentDailyLow * 0.95, recentDailyLow * 1.1}
-	stopLoss := recentDailyLow * 0.85
-	profitTarget := []float64{currentPrice * 1.3, currentPrice * 1.8, currentPrice * 2.5}
+	support, resistance := recentDailyLow, recentDailyHigh
+	supports := []float64{recentDailyLow, weeklyLow}
+	resistances := []float64{weeklyHigh, recentDailyHigh}
+
+	// Volume profile since listing: the most traded prices are the levels that hold
+	if profile := coin.Profile; profile != nil {
+		below, above := profile.supportsBelow(currentPrice), profile.resistancesAbove(currentPrice)
+		supports = append(supports, below...)
+		resistances = append(resistances, above...)
+		if len(below) > 0 {
+			support = findMax(below)
+		}
+		if len(above) > 0 {
+			resistance = findMin(above)
+		}
+		if zone, ok := profile.accumulationZone(currentPrice); ok {
+			accumulationRange = []float64{zone.Low, zone.High}
+		}
+	}
+
+	levels := computeTradeLevels(currentPrice, indicators.ATR, klines, supports, resistances, loadLevelConfig())
 
 	// Generate summaries based on daily market structure
 	technicalSummary := generateDailyTechnicalSummary(coin, shortTermTrend, mediumTermTrend, isDailySupport, volumeTrend)
+	technicalSummary += ", " + generateIndicatorSummary(indicators)
+
+	// Reject setups that do not pay enough for the risk to the stop
+	if levels.Rejected && shouldAccumulate {
+		shouldAccumulate = false
+		confidence = "ต่ำ"
+		recommendedAction = "รอ"
+		technicalSummary += ", " + levels.RejectReason
+	}
 	marketSentiment := generateMarketSentiment(coin.PriceChange, volumeTrend)
 	volumeAnalysis := generateVolumeAnalysis(recentDailyVolume, avgDailyVolume)
 	priceAction := generateDailyPriceAction(currentPrice, recentDailyHigh, recentDailyLow, weeklyHigh, weeklyLow)
@@ -626,26 +1163,36 @@ func performAIAnalysis(coin CoinInfo, klines []Kline) AINewCoinAnalysis {
 		Symbol:            coin.Symbol,
 		Price:             currentPrice,
 		AgeDays:           coin.AgeDays,
+		Score:             coin.Score,
+		ScoreModel:        coin.ScoreModel,
+		ScoreBreakdown:    coin.Breakdown,
 		ShouldAccumulate:  shouldAccumulate,
 		ReverseSignal:     reverseSignal,
 		Confidence:        confidence,
 		RiskLevel:         riskLevel,
 		RecommendedAction: recommendedAction,
 		AccumulationRange: accumulationRange,
-		StopLoss:          stopLoss,
-		ProfitTarget:      profitTarget,
+		StopLoss:          levels.Stop.Price,
+		ProfitTarget:      levels.targetPrices(),
+		Levels:            levels,
 		TechnicalSummary:  technicalSummary,
 		MarketSentiment:   marketSentiment,
 		VolumeAnalysis:    volumeAnalysis,
 		PriceAction:       priceAction,
-		TimeFrame:         "1d",
+		TimeFrame:         interval,
 		LastUpdate:        time.Now(),
+		Indicators:        indicators,
+		Trend:             trend,
+		Support:           support,
+		Resistance:        resistance,
+		VolumeProfile:     coin.Profile,
+		Windows:           windows,
 	}
 }
 
 // Helper functions for technical analysis
 func calculateSMA(prices []float64, period int) []float64 {
-	if len(prices) < period {
+	if period <= 0 || len(prices) < period {
 		return []float64{}
 	}
 
@@ -746,6 +1293,37 @@ func generateDailyTechnicalSummary(coin CoinInfo, shortTrend, mediumTrend, nearS
 	return strings.Join(summary, ", ")
 }
 
+// generateIndicatorSummary describes RSI, MACD, Bollinger position and OBV
+func generateIndicatorSummary(ind TechnicalIndicators) string {
+	summary := []string{fmt.Sprintf("RSI %.0f", ind.RSI)}
+
+	if ind.Overbought {
+		summary = append(summary, "overbought")
+	} else if ind.Oversold {
+		summary = append(summary, "oversold")
+	}
+
+	if ind.MomentumUp {
+		summary = append(summary, "MACD โมเมนตัมขาขึ้น")
+	} else if ind.MACDHistogram < 0 {
+		summary = append(summary, "MACD โมเมนตัมขาลง")
+	}
+
+	if ind.BollingerPercentB > 1 {
+		summary = append(summary, "ทะลุ Bollinger บน")
+	} else if ind.BollingerPercentB < 0 {
+		summary = append(summary, "หลุด Bollinger ล่าง")
+	}
+
+	if ind.OBVRising {
+		summary = append(summary, "OBV สะสม")
+	} else {
+		summary = append(summary, "OBV แจกจ่าย")
+	}
+
+	return strings.Join(summary, ", ")
+}
+
 // Generate daily price action analysis
 func generateDailyPriceAction(current, dailyHigh, dailyLow, weeklyHigh, weeklyLow float64) string {
 	// Daily range analysis
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runCancelCommand handles: cancel [--dry-run] all | SYMBOL [SYMBOL...]
+func runCancelCommand(args []string) error {
+	usage := "ใช้งาน: cancel [--dry-run] all | SYMBOL [SYMBOL...]"
+
+	dryRun := false
+	var symbols []string
+	for _, arg := range args {
+		switch arg {
+		case "--dry-run", "-n":
+			dryRun = true
+		default:
+			// Accept both "A B" and "A,B"
+			for _, symbol := range strings.Split(arg, ",") {
+				if symbol != "" {
+					symbols = append(symbols, strings.ToUpper(symbol))
+				}
+			}
+		}
+	}
+	if len(symbols) == 0 {
+		return errors.New(usage)
+	}
+
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	ordersBySymbol, err := collectOpenOrders(client, symbols)
+	if err != nil {
+		return err
+	}
+
+	if len(ordersBySymbol) == 0 {
+		fmt.Println("✅ ไม่มี orders ที่ต้องยกเลิก")
+		return nil
+	}
+
+	targets := make([]string, 0, len(ordersBySymbol))
+	for symbol := range ordersBySymbol {
+		targets = append(targets, symbol)
+	}
+	sort.Strings(targets)
+
+	if dryRun {
+		fmt.Println("🔍 Dry run - orders ที่จะถูกยกเลิก:")
+	}
+
+	total, canceledTotal := 0, 0
+	for _, symbol := range targets {
+		orders := ordersBySymbol[symbol]
+		total += len(orders)
+		printOrderPreview(symbol, orders)
+
+		if dryRun {
+			continue
+		}
+
+		canceled, err := cancelAllOrders(client, symbol)
+		if err != nil {
+			fmt.Printf("❌ ไม่สามารถยกเลิก orders ของ %s: %v\n", symbol, err)
+			continue
+		}
+		canceledTotal += len(canceled)
+		fmt.Printf("🎯 ยกเลิกสำเร็จ %d/%d orders สำหรับ %s\n", len(canceled), len(orders), symbol)
+	}
+
+	if dryRun {
+		fmt.Printf("\n📋 รวม %d orders ใน %d สัญลักษณ์ (ยังไม่ได้ยกเลิก)\n", total, len(targets))
+		return nil
+	}
+
+	fmt.Printf("\n✅ ยกเลิกสำเร็จ %d/%d orders ใน %d สัญลักษณ์\n", canceledTotal, total, len(targets))
+	return printCancelBalanceSummary(client, targets)
+}
+
+// collectOpenOrders groups open orders by symbol; "all" means every symbol with open orders
+func collectOpenOrders(client *BinanceClient, symbols []string) (map[string][]OrderStatus, error) {
+	ordersBySymbol := make(map[string][]OrderStatus)
+
+	if len(symbols) == 1 && symbols[0] == "ALL" {
+		orders, err := getOpenOrders(client, "")
+		if err != nil {
+			return nil, fmt.Errorf("ไม่สามารถดึง open orders: %v", err)
+		}
+		for _, order := range orders {
+			ordersBySymbol[order.Symbol] = append(ordersBySymbol[order.Symbol], order)
+		}
+		return ordersBySymbol, nil
+	}
+
+	for _, symbol := range symbols {
+		orders, err := getOpenOrders(client, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("ไม่สามารถดึง open orders ของ %s: %v", symbol, err)
+		}
+		if len(orders) == 0 {
+			fmt.Printf("✅ ไม่มี orders ที่ต้องยกเลิกสำหรับ %s\n", symbol)
+			continue
+		}
+		ordersBySymbol[symbol] = orders
+	}
+	return ordersBySymbol, nil
+}
+
+// printOrderPreview lists the open orders of one symbol
+func printOrderPreview(symbol string, orders []OrderStatus) {
+	fmt.Printf("🗑️ %s: %d orders\n", symbol, len(orders))
+	for _, order := range orders {
+		fmt.Printf("   • #%d %-4s %-6s ราคา %s จำนวน %s (จับคู่แล้ว %s)\n",
+			order.OrderID, order.Side, order.Type, order.Price, order.OrigQty, order.ExecutedQty)
+	}
+}
+
+// printCancelBalanceSummary shows USDT and the freed base assets with their USDT value
+func printCancelBalanceSummary(client *BinanceClient, symbols []string) error {
+	balances, err := getBalances(client)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึงยอดเงิน: %v", err)
+	}
+
+	fmt.Println("💰 ยอดคงเหลือหลังยกเลิก:")
+	total := balances["USDT"]
+	fmt.Printf("   USDT: %.2f\n", balances["USDT"])
+
+	for _, symbol := range symbols {
+		if !strings.HasSuffix(symbol, "USDT") {
+			continue
+		}
+		asset := getBaseCoin(symbol)
+		amount := balances[asset]
+		if amount <= 0 {
+			continue
+		}
+
+		price, err := getCurrentPriceForSymbol(client, symbol)
+		if err != nil {
+			fmt.Printf("   %s: %s (ไม่สามารถดึงราคา)\n", asset, strconv.FormatFloat(amount, 'f', -1, 64))
+			continue
+		}
+		value := amount * price
+		total += value
+		fmt.Printf("   %s: %s ($%.2f @ $%.8f)\n", asset, strconv.FormatFloat(amount, 'f', -1, 64), value, price)
+	}
+
+	fmt.Printf("   รวม: $%.2f\n", total)
+	return nil
+}
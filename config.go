@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadEnvFile loads KEY=VALUE pairs from a .env file without overriding
+// variables that are already set in the environment
+func loadEnvFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		if _, exists := os.LookupEnv(key); !exists {
+			os.Setenv(key, value)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// newClientFromEnv creates an authenticated client from BINANCE_API_KEY and either
+// BINANCE_API_SECRET (hmac) or the PEM file in BINANCE_PRIVATE_KEY_PATH (rsa, ed25519),
+// selected by BINANCE_KEY_TYPE
+func newClientFromEnv() (*BinanceClient, error) {
+	loadEnvFile(".env")
+
+	secret := os.Getenv("BINANCE_API_SECRET")
+	if secret == "" {
+		// Older setups used BINANCE_SECRET_KEY
+		secret = os.Getenv("BINANCE_SECRET_KEY")
+	}
+
+	apiKey := os.Getenv("BINANCE_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("กรุณาตั้งค่า BINANCE_API_KEY ในไฟล์ .env")
+	}
+
+	signer, err := newSigner(os.Getenv("BINANCE_KEY_TYPE"), secret, os.Getenv("BINANCE_PRIVATE_KEY_PATH"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &BinanceClient{
+		APIKey:    apiKey,
+		SecretKey: secret,
+		Signer:    signer,
+	}, nil
+}
+
+// envFloat reads a float setting, falling back to def when missing or invalid
+func envFloat(key string, def float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envInt reads an integer setting, falling back to def when missing or invalid
+func envInt(key string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envPeriod reads an indicator period, warning and falling back to def when it is not a number of at least 1
+func envPeriod(key string, def int) int {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		fmt.Printf("⚠️ %s: %q ไม่ถูกต้อง (ต้องเป็นจำนวนเต็มตั้งแต่ 1) - ใช้ค่าเริ่มต้น %d\n", key, raw, def)
+		return def
+	}
+	return value
+}
+
+// envBool reads a boolean setting, falling back to def when missing and warning when invalid
+func envBool(key string, def bool) bool {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		fmt.Printf("⚠️ %s: %q ไม่ถูกต้อง - ใช้ค่าเริ่มต้น %v\n", key, raw, def)
+		return def
+	}
+	return value
+}
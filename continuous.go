@@ -1,5 +1,243 @@
-//go:build ignore
-
-// Continuous grid bot removed.
 package main
 
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	daemonStateFile = "daemon.json"
+	// Score moves smaller than this are treated as noise between cycles
+	daemonScoreDelta = 5.0
+)
+
+// CoinSnapshot represents what the daemon remembers about a coin from the previous cycle
+type CoinSnapshot struct {
+	Symbol           string  `json:"symbol"`
+	Price            float64 `json:"price"`
+	Score            float64 `json:"score"`
+	Signal           string  `json:"signal"` // RecommendedAction of the latest analysis
+	ShouldAccumulate bool    `json:"shouldAccumulate"`
+	ReverseSignal    bool    `json:"reverseSignal"`
+}
+
+// DaemonState represents the scan results kept between cycles
+type DaemonState struct {
+	Cycle     int                     `json:"cycle"`
+	Coins     map[string]CoinSnapshot `json:"coins"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+// ScanDaemon repeats the scan and AI analysis on a fixed interval
+type ScanDaemon struct {
+	interval time.Duration
+	state    DaemonState
+	client   *BinanceClient // nil when no API keys are configured: scan only
+}
+
+// newScanDaemon creates a daemon using ANALYSIS_INTERVAL (seconds) and the last saved state
+func newScanDaemon() (*ScanDaemon, error) {
+	loadEnvFile(".env")
+
+	d := &ScanDaemon{
+		interval: time.Duration(envInt("ANALYSIS_INTERVAL", 60)) * time.Second,
+		state:    DaemonState{Coins: map[string]CoinSnapshot{}},
+	}
+
+	if _, err := loadState(daemonStateFile, &d.state); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลดสถานะ daemon: %v", err)
+	}
+	if d.state.Coins == nil {
+		d.state.Coins = map[string]CoinSnapshot{}
+	}
+
+	// Trading features (equity guard, DCA, trailing stops) need API keys; without them the daemon only scans
+	if client, err := newClientFromEnv(); err == nil {
+		if err := reconcileOnStartup(client); err != nil {
+			return nil, err
+		}
+		d.client = client
+	} else {
+		fmt.Printf("ℹ️ %v - daemon จะสแกนอย่างเดียว (ไม่มี equity guard, แผน DCA และ trailing stops)\n", err)
+	}
+
+	return d, nil
+}
+
+// runCycle scans, analyzes and reports what changed since the previous cycle
+func (d *ScanDaemon) runCycle() error {
+	d.state.Cycle++
+	fmt.Printf("\n⏱️ รอบที่ %d - %s\n", d.state.Cycle, time.Now().Format("2006-01-02 15:04:05"))
+
+	coins, err := scanBestCoins()
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถสแกนเหรียญได้: %v", err)
+	}
+
+	analyses, err := analyzeCoinsForAccumulation(coins)
+	if err != nil {
+		fmt.Printf("⚠️ AI analysis ล้มเหลว: %v\n", err)
+	}
+
+	current := make(map[string]CoinSnapshot, len(coins))
+	for _, coin := range coins {
+		current[coin.Symbol] = CoinSnapshot{Symbol: coin.Symbol, Price: coin.Price, Score: coin.Score}
+	}
+	for _, analysis := range analyses {
+		snapshot, ok := current[analysis.Symbol]
+		if !ok {
+			continue
+		}
+		snapshot.Signal = analysis.RecommendedAction
+		snapshot.ShouldAccumulate = analysis.ShouldAccumulate
+		snapshot.ReverseSignal = analysis.ReverseSignal
+		current[analysis.Symbol] = snapshot
+	}
+
+	// The first cycle has nothing to compare against
+	if d.state.Cycle > 1 || len(d.state.Coins) > 0 {
+		printCycleChanges(d.state.Coins, current)
+	} else {
+		fmt.Printf("📋 รอบแรก: ติดตาม %d เหรียญ\n", len(current))
+	}
+
+	if d.client != nil {
+		// Check drawdown first so a tripped kill switch refuses this cycle's DCA buys
+		if err := checkEquityGuard(d.client); err != nil {
+			fmt.Printf("⚠️ Equity guard: %v\n", err)
+		}
+		if err := runDueDCAPlans(d.client, analyses); err != nil {
+			fmt.Printf("⚠️ DCA: %v\n", err)
+		}
+		if prices, err := getAllPrices(); err != nil {
+			fmt.Printf("⚠️ Trailing stops: ไม่สามารถดึงราคา: %v\n", err)
+		} else if err := checkTrailingStops(d.client, prices); err != nil {
+			fmt.Printf("⚠️ Trailing stops: %v\n", err)
+		}
+	}
+
+	d.state.Coins = current
+	d.state.UpdatedAt = time.Now()
+	return saveState(daemonStateFile, d.state)
+}
+
+// printCycleChanges reports new entrants, dropped coins and coins whose signal or score moved
+func printCycleChanges(previous, current map[string]CoinSnapshot) {
+	var added, dropped, changed []string
+
+	for _, symbol := range sortedSnapshotKeys(current) {
+		now := current[symbol]
+		before, existed := previous[symbol]
+		if !existed {
+			added = append(added, fmt.Sprintf("%s (คะแนน %.1f, %s)", symbol, now.Score, signalLabel(now)))
+			continue
+		}
+
+		var notes []string
+		if now.Signal != before.Signal || now.ReverseSignal != before.ReverseSignal {
+			notes = append(notes, fmt.Sprintf("สัญญาณ %s → %s", signalLabel(before), signalLabel(now)))
+		}
+		if diff := now.Score - before.Score; diff >= daemonScoreDelta || diff <= -daemonScoreDelta {
+			notes = append(notes, fmt.Sprintf("คะแนน %.1f → %.1f", before.Score, now.Score))
+		}
+		if len(notes) > 0 {
+			changed = append(changed, fmt.Sprintf("%s: %s", symbol, strings.Join(notes, ", ")))
+		}
+	}
+
+	for _, symbol := range sortedSnapshotKeys(previous) {
+		if _, stillThere := current[symbol]; !stillThere {
+			dropped = append(dropped, symbol)
+		}
+	}
+
+	if len(added) == 0 && len(dropped) == 0 && len(changed) == 0 {
+		fmt.Println("😴 ไม่มีการเปลี่ยนแปลงจากรอบก่อน")
+		return
+	}
+
+	fmt.Println("📋 การเปลี่ยนแปลงจากรอบก่อน:")
+	for _, line := range added {
+		fmt.Printf("   🆕 %s\n", line)
+	}
+	for _, symbol := range dropped {
+		fmt.Printf("   ➖ %s หลุดจากรายการ\n", symbol)
+	}
+	for _, line := range changed {
+		fmt.Printf("   🔄 %s\n", line)
+	}
+}
+
+// signalLabel describes a snapshot's signal for change reports
+func signalLabel(s CoinSnapshot) string {
+	label := s.Signal
+	if label == "" {
+		label = "ไม่มีผลวิเคราะห์"
+	}
+	if s.ReverseSignal {
+		label += "+กลับตัว"
+	}
+	return label
+}
+
+// sortedSnapshotKeys returns the snapshot symbols in order
+func sortedSnapshotKeys(coins map[string]CoinSnapshot) []string {
+	keys := make([]string, 0, len(coins))
+	for symbol := range coins {
+		keys = append(keys, symbol)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Run loops until SIGINT/SIGTERM; a signal during a cycle lets it finish first, a second one exits immediately
+func (d *ScanDaemon) Run() error {
+	stop := make(chan os.Signal, 2)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	fmt.Printf("🔁 โหมดสแกนต่อเนื่อง ทุก %v (Ctrl+C เพื่อหยุด)\n", d.interval)
+
+	for {
+		done := make(chan error, 1)
+		go func() { done <- d.runCycle() }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				fmt.Printf("⚠️ %v\n", err)
+			}
+		case <-stop:
+			fmt.Println("\n🛑 ได้รับสัญญาณหยุด รอให้รอบปัจจุบันเสร็จ (กดอีกครั้งเพื่อออกทันที)...")
+			select {
+			case <-done:
+			case <-stop:
+				return fmt.Errorf("หยุดกลางรอบ สถานะรอบนี้ไม่ถูกบันทึก")
+			}
+			fmt.Println("💾 บันทึกสถานะแล้ว ออกจากโหมดต่อเนื่อง")
+			return nil
+		}
+
+		fmt.Printf("⏳ รอบถัดไปใน %v\n", d.interval)
+		select {
+		case <-stop:
+			fmt.Println("\n💾 บันทึกสถานะแล้ว ออกจากโหมดต่อเนื่อง")
+			return nil
+		case <-time.After(d.interval):
+		}
+	}
+}
+
+// runDaemonCommand handles: daemon
+func runDaemonCommand(args []string) error {
+	daemon, err := newScanDaemon()
+	if err != nil {
+		return err
+	}
+	return daemon.Run()
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dcaStateFile = "dca.json"
+
+// RecommendedAction that pauses DCA buys
+const dcaPauseAction = "หลีกเลี่ยง"
+
+// Backoff for buys that failed on a transient error: doubles from the first delay up to the cap
+const (
+	dcaRetryDelay    = time.Minute
+	dcaRetryMaxDelay = time.Hour
+)
+
+// DCABuy represents one executed DCA purchase
+type DCABuy struct {
+	Time     time.Time `json:"time"`
+	OrderID  string    `json:"orderId"`
+	Quantity float64   `json:"quantity"`
+	Cost     float64   `json:"cost"` // USDT spent
+	Price    float64   `json:"price"`
+}
+
+// DCAPlan represents a scheduled dollar-cost-averaging plan for one symbol
+type DCAPlan struct {
+	Symbol       string    `json:"symbol"`
+	AmountPerBuy float64   `json:"amountPerBuy"` // USDT per buy
+	Interval     string    `json:"interval"`     // "daily", "weekly", "12h", "3d", ...
+	EndDate      time.Time `json:"endDate"`
+	PriceCeiling float64   `json:"priceCeiling,omitempty"` // 0 = no ceiling
+	StopLoss     float64   `json:"stopLoss,omitempty"`     // buys are sized so hitting it loses at most RISK_PERCENTAGE; 0 = none
+	Paused       bool      `json:"paused"`
+	NextBuy      time.Time `json:"nextBuy"`
+	LastSkip     string    `json:"lastSkip,omitempty"`
+	Failures     int       `json:"failures,omitempty"` // consecutive transient failures of the current buy
+	RetryAt      time.Time `json:"retryAt,omitempty"`
+	Buys         []DCABuy  `json:"buys"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// DCAState represents every DCA plan, keyed by symbol
+type DCAState struct {
+	Plans map[string]*DCAPlan `json:"plans"`
+}
+
+// parseDCAInterval accepts "daily", "weekly", Go durations ("12h") and day counts ("3d")
+func parseDCAInterval(interval string) (time.Duration, error) {
+	switch interval {
+	case "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	}
+
+	if strings.HasSuffix(interval, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(interval, "d"))
+		if err == nil && days > 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+
+	duration, err := time.ParseDuration(interval)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("รอบการซื้อไม่ถูกต้อง: %s", interval)
+	}
+	return duration, nil
+}
+
+// loadDCAState reads every DCA plan
+func loadDCAState() (*DCAState, error) {
+	state := &DCAState{}
+	if _, err := loadState(dcaStateFile, state); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลดแผน DCA: %v", err)
+	}
+	if state.Plans == nil {
+		state.Plans = map[string]*DCAPlan{}
+	}
+	return state, nil
+}
+
+// save persists the DCA plans
+func (s *DCAState) save() error {
+	return saveState(dcaStateFile, s)
+}
+
+// sortedPlans returns the plans ordered by symbol
+func (s *DCAState) sortedPlans() []*DCAPlan {
+	plans := make([]*DCAPlan, 0, len(s.Plans))
+	for _, plan := range s.Plans {
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Symbol < plans[j].Symbol
+	})
+	return plans
+}
+
+// Totals returns quantity bought, USDT spent and the average entry price
+func (p *DCAPlan) Totals() (quantity, cost, avgPrice float64) {
+	for _, buy := range p.Buys {
+		quantity += buy.Quantity
+		cost += buy.Cost
+	}
+	if quantity > 0 {
+		avgPrice = cost / quantity
+	}
+	return quantity, cost, avgPrice
+}
+
+// skipReason applies the skip rules; an empty string means the buy may go ahead
+func (p *DCAPlan) skipReason(price float64, analysis *AINewCoinAnalysis) string {
+	if p.PriceCeiling > 0 && price > p.PriceCeiling {
+		return fmt.Sprintf("ราคา $%.8f สูงกว่าเพดาน $%.8f", price, p.PriceCeiling)
+	}
+	if analysis != nil && analysis.RecommendedAction == dcaPauseAction {
+		return fmt.Sprintf("AI แนะนำ \"%s\" (ความเสี่ยง %s)", analysis.RecommendedAction, analysis.RiskLevel)
+	}
+	return ""
+}
+
+// executeDCABuy market-buys AmountPerBuy worth of the coin and records the fill
+func executeDCABuy(client *BinanceClient, plan *DCAPlan, price float64) error {
+	rules, err := getSymbolRules(plan.Symbol)
+	if err != nil {
+		return err
+	}
+
+	quantity := rules.formatQuantity(plan.AmountPerBuy / price)
+	// One order per scheduled slot, however often the slot is retried
+	tag := OrderTag{Strategy: strategyDCA, Key: strconv.FormatInt(plan.NextBuy.Unix(), 10), Stop: plan.StopLoss}
+	orderID, err := placeOrder(client, tag, plan.Symbol, "BUY", "MARKET", quantity, "")
+	if err != nil {
+		return err
+	}
+
+	buy := DCABuy{Time: time.Now(), OrderID: orderID}
+	buy.Quantity, _ = strconv.ParseFloat(quantity, 64)
+	buy.Cost = buy.Quantity * price
+
+	// Use the exact fill when Binance reports it
+	if order, err := getOrder(client, plan.Symbol, orderID); err == nil {
+		executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+		quote, _ := strconv.ParseFloat(order.CummulativeQuoteQty, 64)
+		if executed > 0 {
+			buy.Quantity, buy.Cost = executed, quote
+		}
+	}
+	if buy.Quantity > 0 {
+		buy.Price = buy.Cost / buy.Quantity
+	}
+
+	plan.Buys = append(plan.Buys, buy)
+	fmt.Printf("🪙 DCA %s: ซื้อ %s @ $%.8f (%.2f USDT)\n", plan.Symbol,
+		strconv.FormatFloat(buy.Quantity, 'f', -1, 64), buy.Price, buy.Cost)
+	return nil
+}
+
+// isTransientOrderError reports whether a failed buy is worth retrying: risk rejections and
+// Binance's own 4xx rejections will fail the same way again, network and server errors may not
+func isTransientOrderError(err error) bool {
+	var riskErr *RiskError
+	if errors.As(err, &riskErr) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// retryBackoff returns the wait before the next attempt after failures consecutive failures
+func retryBackoff(failures int) time.Duration {
+	delay := dcaRetryDelay
+	for i := 1; i < failures && delay < dcaRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > dcaRetryMaxDelay {
+		delay = dcaRetryMaxDelay
+	}
+	return delay
+}
+
+// runDueDCAPlans executes every plan whose next buy is due; analyses are the latest scan results
+func runDueDCAPlans(client *BinanceClient, analyses []AINewCoinAnalysis) error {
+	state, err := loadDCAState()
+	if err != nil {
+		return err
+	}
+
+	latest := make(map[string]*AINewCoinAnalysis, len(analyses))
+	for i := range analyses {
+		latest[analyses[i].Symbol] = &analyses[i]
+	}
+
+	now := time.Now()
+	for _, plan := range state.sortedPlans() {
+		if plan.Paused || now.Before(plan.NextBuy) || now.Before(plan.RetryAt) {
+			continue
+		}
+		if !plan.EndDate.IsZero() && now.After(plan.EndDate) {
+			plan.Paused = true
+			plan.LastSkip = "สิ้นสุดแผนแล้ว"
+			fmt.Printf("🏁 แผน DCA %s สิ้นสุดแล้ว\n", plan.Symbol)
+			continue
+		}
+
+		interval, err := parseDCAInterval(plan.Interval)
+		if err != nil {
+			fmt.Printf("⚠️ แผน DCA %s: %v\n", plan.Symbol, err)
+			continue
+		}
+
+		price, err := getCurrentPriceForSymbol(client, plan.Symbol)
+		if err != nil {
+			fmt.Printf("⚠️ แผน DCA %s: ไม่สามารถดึงราคา: %v\n", plan.Symbol, err)
+			continue
+		}
+
+		// Coins outside the scan results get their own analysis
+		analysis := latest[plan.Symbol]
+		if analysis == nil {
+			if analysis, err = analyzeSymbol(client, plan.Symbol); err != nil {
+				fmt.Printf("⚠️ แผน DCA %s: วิเคราะห์ไม่สำเร็จ (%v) - ซื้อตามแผนต่อ\n", plan.Symbol, err)
+			}
+		}
+
+		if reason := plan.skipReason(price, analysis); reason != "" {
+			plan.LastSkip = reason
+			plan.Failures, plan.RetryAt = 0, time.Time{}
+			plan.NextBuy = now.Add(interval)
+			fmt.Printf("⏸️ DCA %s ข้ามรอบนี้: %s\n", plan.Symbol, reason)
+			continue
+		}
+
+		if err := executeDCABuy(client, plan, price); err != nil {
+			plan.LastSkip = err.Error()
+			plan.Failures++
+			retryAt := now.Add(retryBackoff(plan.Failures))
+			// Rejections would fail every retry, and a retry past the next slot would buy twice in a row
+			if !isTransientOrderError(err) || !retryAt.Before(plan.NextBuy.Add(interval)) {
+				plan.NextBuy = now.Add(interval)
+				plan.Failures, plan.RetryAt = 0, time.Time{}
+				fmt.Printf("⏭️ DCA %s ข้ามรอบนี้: %v\n", plan.Symbol, err)
+				continue
+			}
+			plan.RetryAt = retryAt
+			fmt.Printf("❌ DCA %s ซื้อไม่สำเร็จ: %v (ลองใหม่ %s)\n", plan.Symbol, err, retryAt.Format("15:04"))
+			continue
+		}
+		plan.LastSkip = ""
+		plan.Failures, plan.RetryAt = 0, time.Time{}
+		plan.NextBuy = now.Add(interval)
+	}
+
+	return state.save()
+}
+
+// printDCAReport shows each plan's average entry against the current price
+func printDCAReport(state *DCAState) {
+	if len(state.Plans) == 0 {
+		fmt.Println("📭 ยังไม่มีแผน DCA")
+		return
+	}
+
+	prices, err := getAllPrices()
+	if err != nil {
+		fmt.Printf("⚠️ ไม่สามารถดึงราคา: %v\n", err)
+	}
+
+	fmt.Println("🪙 แผน DCA:")
+	fmt.Println("สัญลักษณ์     | ต่อครั้ง  | รอบ     | ซื้อแล้ว | ลงทุนแล้ว  | ราคาเฉลี่ย     | ราคาปัจจุบัน   | กำไร/ขาดทุน | สถานะ")
+	fmt.Println("--------------|----------|---------|---------|-----------|---------------|---------------|------------|------")
+
+	for _, plan := range state.sortedPlans() {
+		quantity, cost, avgPrice := plan.Totals()
+		current := prices[plan.Symbol]
+
+		change := 0.0
+		if avgPrice > 0 && current > 0 {
+			change = (current - avgPrice) / avgPrice * 100
+		}
+
+		next := plan.NextBuy
+		if plan.RetryAt.After(next) {
+			next = plan.RetryAt
+		}
+		status := "ทำงาน - ครั้งถัดไป " + next.Format("2006-01-02 15:04")
+		if plan.Paused {
+			status = "หยุด"
+		}
+		if plan.LastSkip != "" {
+			status += " (" + plan.LastSkip + ")"
+		}
+
+		fmt.Printf("%-13s | %8.2f | %-7s | %7d | %9.2f | $%-12.8f | $%-12.8f | %+9.1f%% | %s\n",
+			plan.Symbol, plan.AmountPerBuy, plan.Interval, len(plan.Buys), cost, avgPrice, current, change, status)
+		if quantity > 0 && current > 0 {
+			fmt.Printf("   ถือ %s มูลค่า $%.2f\n", strconv.FormatFloat(quantity, 'f', -1, 64), quantity*current)
+		}
+	}
+}
+
+// runDCACommand handles: dca add|list|pause|resume|remove|run
+func runDCACommand(args []string) error {
+	usage := "ใช้งาน: dca add SYMBOL AMOUNT INTERVAL END_DATE(YYYY-MM-DD) [PRICE_CEILING] [STOP_LOSS] | dca list | dca pause|resume|remove SYMBOL | dca run"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	state, err := loadDCAState()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 5 {
+			return errors.New(usage)
+		}
+		symbol := strings.ToUpper(args[1])
+		amount, err := strconv.ParseFloat(args[2], 64)
+		if err != nil || amount <= 0 {
+			return fmt.Errorf("จำนวนเงินต่อครั้งไม่ถูกต้อง: %s", args[2])
+		}
+		if _, err := parseDCAInterval(args[3]); err != nil {
+			return err
+		}
+		endDate, err := time.ParseInLocation("2006-01-02", args[4], time.Local)
+		if err != nil {
+			return fmt.Errorf("วันสิ้นสุดไม่ถูกต้อง: %s", args[4])
+		}
+
+		plan := &DCAPlan{
+			Symbol:       symbol,
+			AmountPerBuy: amount,
+			Interval:     args[3],
+			EndDate:      endDate.Add(24*time.Hour - time.Second),
+			NextBuy:      time.Now(),
+			CreatedAt:    time.Now(),
+		}
+		if len(args) > 5 {
+			if plan.PriceCeiling, err = strconv.ParseFloat(args[5], 64); err != nil {
+				return fmt.Errorf("เพดานราคาไม่ถูกต้อง: %s", args[5])
+			}
+		}
+		if len(args) > 6 {
+			if plan.StopLoss, err = strconv.ParseFloat(args[6], 64); err != nil || plan.StopLoss < 0 {
+				return fmt.Errorf("stop loss ไม่ถูกต้อง: %s", args[6])
+			}
+		}
+		if existing, ok := state.Plans[symbol]; ok {
+			// Keep the purchase history when a plan is redefined
+			plan.Buys = existing.Buys
+		}
+		state.Plans[symbol] = plan
+		fmt.Printf("✅ เพิ่มแผน DCA %s: %.2f USDT ทุก %s ถึง %s\n", symbol, amount, plan.Interval, args[4])
+
+	case "pause", "resume", "remove":
+		if len(args) < 2 {
+			return errors.New(usage)
+		}
+		symbol := strings.ToUpper(args[1])
+		plan, ok := state.Plans[symbol]
+		if !ok {
+			return fmt.Errorf("ไม่พบแผน DCA สำหรับ %s", symbol)
+		}
+		switch args[0] {
+		case "pause":
+			plan.Paused = true
+		case "resume":
+			plan.Paused = false
+			plan.LastSkip = ""
+			plan.Failures, plan.RetryAt = 0, time.Time{}
+		case "remove":
+			delete(state.Plans, symbol)
+		}
+		fmt.Printf("✅ %s แผน DCA %s แล้ว\n", args[0], symbol)
+
+	case "list":
+		printDCAReport(state)
+		return nil
+
+	case "run":
+		client, err := newClientFromEnv()
+		if err != nil {
+			return err
+		}
+		if err := reconcileOnStartup(client); err != nil {
+			return err
+		}
+		if err := runDueDCAPlans(client, nil); err != nil {
+			return err
+		}
+		state, err = loadDCAState()
+		if err != nil {
+			return err
+		}
+		printDCAReport(state)
+		return nil
+
+	default:
+		return errors.New(usage)
+	}
+
+	return state.save()
+}
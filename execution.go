@@ -0,0 +1,486 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	executionsFile = "executions.json"
+	// Only the most recent execution reports are kept
+	maxExecutionReports = 100
+	// How often a resting iceberg clip is checked
+	execPollInterval = 5 * time.Second
+)
+
+// ExecutionFill represents one child order of an algorithmic execution
+type ExecutionFill struct {
+	OrderID  string    `json:"orderId"`
+	Quantity float64   `json:"quantity"`
+	Quote    float64   `json:"quote"` // USDT value of the fill
+	Time     time.Time `json:"time"`
+}
+
+// ExecutionReport represents the outcome of a TWAP or iceberg execution
+type ExecutionReport struct {
+	Algo         string          `json:"algo"` // "twap" or "iceberg"
+	Symbol       string          `json:"symbol"`
+	Side         string          `json:"side"`
+	Requested    float64         `json:"requested"`
+	Filled       float64         `json:"filled"`
+	AvgPrice     float64         `json:"avgPrice"`
+	ArrivalPrice float64         `json:"arrivalPrice"` // price when the execution started
+	SlippageBps  float64         `json:"slippageBps"`  // positive = worse than arrival
+	Fills        []ExecutionFill `json:"fills"`
+	Note         string          `json:"note,omitempty"` // why the execution ended early
+	StartedAt    time.Time       `json:"startedAt"`
+	FinishedAt   time.Time       `json:"finishedAt"`
+}
+
+// Executor slices a parent order into child orders sent through placeOrder
+type Executor struct {
+	client        *BinanceClient
+	rules         *SymbolRules
+	symbol        string
+	side          string
+	participation float64 // max share of observed market volume, 0-1
+	stopLoss      float64 // BUY only: stop the position is sized against by RISK_PERCENTAGE, 0 = none
+	orders        int     // child orders placed; keys the next child's client order ID
+	stop          chan os.Signal
+	report        ExecutionReport
+}
+
+// newExecutor records the arrival price and reads EXEC_MAX_PARTICIPATION (percent)
+func newExecutor(client *BinanceClient, algo, symbol, side string, quantity float64) (*Executor, error) {
+	rules, err := getSymbolRules(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	arrival, err := getCurrentPriceForSymbol(client, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึงราคา %s: %v", symbol, err)
+	}
+
+	return &Executor{
+		client:        client,
+		rules:         rules,
+		symbol:        symbol,
+		side:          side,
+		participation: envFloat("EXEC_MAX_PARTICIPATION", 10) / 100,
+		report: ExecutionReport{
+			Algo:         algo,
+			Symbol:       symbol,
+			Side:         side,
+			Requested:    quantity,
+			ArrivalPrice: arrival,
+			StartedAt:    time.Now(),
+		},
+	}, nil
+}
+
+// remaining returns the quantity still to execute
+func (e *Executor) remaining() float64 {
+	return e.report.Requested - e.report.Filled
+}
+
+// observedVolume sums the market's base-asset volume over the last window using 1m klines
+func observedVolume(symbol string, window time.Duration) (float64, error) {
+	minutes := int(math.Ceil(window.Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	klines, err := getKlines(&BinanceClient{}, symbol, "1m", min(minutes+1, 1000))
+	if err != nil {
+		return 0, err
+	}
+
+	return volumeSince(klines, time.Now().Add(-window)), nil
+}
+
+// volumeSince sums the volume of candles closing at or after since
+func volumeSince(klines []Kline, since time.Time) float64 {
+	volume := 0.0
+	for _, k := range klines {
+		if k.CloseTime >= since.UnixMilli() {
+			volume += k.Volume
+		}
+	}
+	return volume
+}
+
+// capToParticipation limits quantity to participation (0-1) of volume; 0 participation means no cap
+func capToParticipation(quantity, volume, participation float64) float64 {
+	if participation <= 0 {
+		return quantity
+	}
+	return math.Min(quantity, volume*participation)
+}
+
+// twapSliceTarget spreads what is left over the slices that are left, so capped slices catch up later
+func twapSliceTarget(remaining float64, slices, done int) float64 {
+	if done >= slices {
+		return remaining
+	}
+	return remaining / float64(slices-done)
+}
+
+// participationCap limits a child order to the configured share of recent volume
+func (e *Executor) participationCap(quantity float64, window time.Duration) float64 {
+	if e.participation <= 0 {
+		return quantity
+	}
+	volume, err := observedVolume(e.symbol, window)
+	if err != nil {
+		fmt.Printf("⚠️ ไม่สามารถดึง volume %s: %v - ใช้ขนาดเต็ม\n", e.symbol, err)
+		return quantity
+	}
+	capped := capToParticipation(quantity, volume, e.participation)
+	if capped < quantity {
+		fmt.Printf("   🚦 จำกัดตาม participation %.0f%%: volume %.4f → สูงสุด %.4f\n", e.participation*100, volume, capped)
+	}
+	return capped
+}
+
+// tradable floors a quantity to the step size, returning "" when it is below the symbol's minimums
+func (e *Executor) tradable(quantity, price float64) string {
+	quantity = floorToStep(quantity, e.rules.StepSize)
+	if quantity <= 0 || quantity < e.rules.MinQty || quantity*price < e.rules.MinNotional {
+		return ""
+	}
+	return e.rules.formatQuantity(quantity)
+}
+
+// recordFill adds a child order's executed quantity to the report
+func (e *Executor) recordFill(order *OrderStatus) {
+	executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	quote, _ := strconv.ParseFloat(order.CummulativeQuoteQty, 64)
+	if executed <= 0 {
+		return
+	}
+
+	e.report.Fills = append(e.report.Fills, ExecutionFill{
+		OrderID:  strconv.FormatInt(order.OrderID, 10),
+		Quantity: executed,
+		Quote:    quote,
+		Time:     time.Now(),
+	})
+	e.report.Filled += executed
+	fmt.Printf("   ✅ fill %s @ $%.8f (รวม %.4f/%.4f)\n",
+		order.ExecutedQty, quote/executed, e.report.Filled, e.report.Requested)
+}
+
+// nextTag names the next child order by the execution's start and the child's position in it
+func (e *Executor) nextTag() OrderTag {
+	e.orders++
+	return OrderTag{Strategy: strategyExecutor, Key: fmt.Sprintf("%d|%d", e.report.StartedAt.UnixNano(), e.orders), Stop: e.stopLoss}
+}
+
+// sendMarket places a MARKET child order and records its fill
+func (e *Executor) sendMarket(quantity string) error {
+	orderID, err := placeOrder(e.client, e.nextTag(), e.symbol, e.side, "MARKET", quantity, "")
+	if err != nil {
+		return err
+	}
+	order, err := getOrder(e.client, e.symbol, orderID)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถตรวจสอบ order %s: %v", orderID, err)
+	}
+	e.recordFill(order)
+	return nil
+}
+
+// wait sleeps for d and reports false when the user asked to stop
+func (e *Executor) wait(d time.Duration) bool {
+	select {
+	case <-e.stop:
+		e.report.Note = "หยุดโดยผู้ใช้"
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// runTWAP executes the parent order in equal slices spread over duration
+func (e *Executor) runTWAP(duration time.Duration, slices int) {
+	sliceInterval := duration / time.Duration(slices)
+	fmt.Printf("⏱️ TWAP %s %s %.4f: %d ส่วน ทุก %v\n", e.side, e.symbol, e.report.Requested, slices, sliceInterval)
+
+	for i := 0; i < slices && e.remaining() > 0; i++ {
+		if i > 0 && !e.wait(sliceInterval) {
+			return
+		}
+
+		target := twapSliceTarget(e.remaining(), slices, i)
+		quantity := e.participationCap(target, sliceInterval)
+
+		price, err := getCurrentPriceForSymbol(e.client, e.symbol)
+		if err != nil {
+			fmt.Printf("⚠️ ส่วนที่ %d: ไม่สามารถดึงราคา: %v\n", i+1, err)
+			continue
+		}
+		formatted := e.tradable(quantity, price)
+		if formatted == "" {
+			fmt.Printf("   ⏭️ ส่วนที่ %d/%d: ขนาดต่ำกว่าขั้นต่ำของ %s - ยกไปส่วนถัดไป\n", i+1, slices, e.symbol)
+			continue
+		}
+
+		fmt.Printf("📤 ส่วนที่ %d/%d: %s %s\n", i+1, slices, e.side, formatted)
+		if err := e.sendMarket(formatted); err != nil {
+			fmt.Printf("❌ ส่วนที่ %d ไม่สำเร็จ: %v\n", i+1, err)
+			var riskErr *RiskError
+			if errors.As(err, &riskErr) {
+				e.report.Note = err.Error()
+				return
+			}
+		}
+	}
+
+	if e.remaining() > 0 && e.report.Note == "" {
+		e.report.Note = "ครบเวลาแล้วแต่ยังไม่ครบจำนวน (participation หรือขั้นต่ำของ order)"
+	}
+}
+
+// runIceberg shows at most visible quantity at a time as LIMIT orders at price, until done or deadline
+func (e *Executor) runIceberg(visible, price float64, deadline time.Time) {
+	formattedPrice := e.rules.formatPrice(price)
+	fmt.Printf("🧊 Iceberg %s %s %.4f @ $%s: แสดงครั้งละ %.4f\n", e.side, e.symbol, e.report.Requested, formattedPrice, visible)
+
+	lastClip := time.Now().Add(-time.Minute)
+	for e.remaining() > 0 {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			e.report.Note = "หมดเวลา"
+			return
+		}
+
+		clip := math.Min(visible, e.remaining())
+		clip = e.participationCap(clip, time.Since(lastClip))
+		formatted := e.tradable(clip, price)
+		if formatted == "" {
+			if floorToStep(e.remaining(), e.rules.StepSize) < e.rules.MinQty {
+				e.report.Note = "ส่วนที่เหลือต่ำกว่าขั้นต่ำของ order"
+				return
+			}
+			// Not enough market volume yet for a clip, wait for more
+			if !e.wait(execPollInterval) {
+				return
+			}
+			continue
+		}
+
+		lastClip = time.Now()
+		orderID, err := placeOrder(e.client, e.nextTag(), e.symbol, e.side, "LIMIT", formatted, formattedPrice)
+		if err != nil {
+			e.report.Note = err.Error()
+			return
+		}
+		fmt.Printf("📤 clip %s %s @ $%s (Order ID: %s)\n", e.side, formatted, formattedPrice, orderID)
+
+		if !e.awaitClip(orderID, deadline) {
+			return
+		}
+	}
+}
+
+// awaitClip polls a resting clip until it is done; on stop or deadline it cancels the clip
+func (e *Executor) awaitClip(orderID string, deadline time.Time) bool {
+	for {
+		keepGoing := e.wait(execPollInterval)
+		if keepGoing && !deadline.IsZero() && time.Now().After(deadline) {
+			e.report.Note = "หมดเวลา"
+			keepGoing = false
+		}
+		if !keepGoing {
+			if err := cancelOrder(e.client, e.symbol, orderID); err != nil && !strings.Contains(err.Error(), "Unknown order") {
+				fmt.Printf("⚠️ ไม่สามารถยกเลิก clip %s: %v\n", orderID, err)
+			}
+		}
+
+		order, err := getOrder(e.client, e.symbol, orderID)
+		if err != nil {
+			fmt.Printf("⚠️ ไม่สามารถตรวจสอบ clip %s: %v\n", orderID, err)
+			if !keepGoing {
+				return false
+			}
+			continue
+		}
+
+		switch order.Status {
+		case "FILLED", "CANCELED", "EXPIRED", "REJECTED":
+			e.recordFill(order)
+			if order.Status != "FILLED" && keepGoing {
+				e.report.Note = fmt.Sprintf("clip %s ถูก %s จากภายนอก", orderID, order.Status)
+				return false
+			}
+			return keepGoing
+		}
+		if !keepGoing {
+			e.recordFill(order)
+			return false
+		}
+	}
+}
+
+// finish computes average fill and slippage versus the arrival price, and saves the report
+func (e *Executor) finish() *ExecutionReport {
+	r := &e.report
+	r.FinishedAt = time.Now()
+	r.summarize()
+
+	var history []ExecutionReport
+	if _, err := loadState(executionsFile, &history); err != nil {
+		fmt.Printf("⚠️ ไม่สามารถโหลดประวัติการ execute: %v\n", err)
+	}
+	history = append(history, *r)
+	if len(history) > maxExecutionReports {
+		history = history[len(history)-maxExecutionReports:]
+	}
+	if err := saveState(executionsFile, history); err != nil {
+		fmt.Printf("⚠️ ไม่สามารถบันทึกประวัติการ execute: %v\n", err)
+	}
+
+	return r
+}
+
+// summarize sets the average fill price and its slippage in basis points against the arrival price;
+// positive slippage is always worse for the side
+func (r *ExecutionReport) summarize() {
+	quote := 0.0
+	for _, fill := range r.Fills {
+		quote += fill.Quote
+	}
+	if r.Filled > 0 {
+		r.AvgPrice = quote / r.Filled
+	}
+	if r.AvgPrice > 0 && r.ArrivalPrice > 0 {
+		r.SlippageBps = (r.AvgPrice - r.ArrivalPrice) / r.ArrivalPrice * 10000
+		if r.Side == "SELL" {
+			r.SlippageBps = -r.SlippageBps
+		}
+	}
+}
+
+// printExecutionReport prints the average fill against the arrival price
+func printExecutionReport(r *ExecutionReport) {
+	fmt.Printf("\n📊 สรุป %s %s %s:\n", strings.ToUpper(r.Algo), r.Side, r.Symbol)
+	fmt.Printf("   จำนวน: %.4f / %.4f (%.1f%%) ใน %d orders\n",
+		r.Filled, r.Requested, r.Filled/r.Requested*100, len(r.Fills))
+	fmt.Printf("   ราคาตอนเริ่ม: $%.8f\n", r.ArrivalPrice)
+	if r.Filled > 0 {
+		fmt.Printf("   ราคาเฉลี่ยที่ได้: $%.8f (slippage %+.1f bps)\n", r.AvgPrice, r.SlippageBps)
+	}
+	fmt.Printf("   ใช้เวลา: %v\n", r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+	if r.Note != "" {
+		fmt.Printf("   ℹ️ %s\n", r.Note)
+	}
+}
+
+// runExecCommand handles: exec twap SYMBOL SIDE QTY DURATION [SLICES] | exec iceberg SYMBOL SIDE QTY VISIBLE PRICE [DURATION],
+// each with an optional --stop PRICE for buys
+func runExecCommand(args []string) error {
+	usage := "ใช้งาน: exec twap SYMBOL BUY|SELL QUANTITY DURATION [SLICES] | exec iceberg SYMBOL BUY|SELL QUANTITY VISIBLE PRICE [MAX_DURATION] (เพิ่ม --stop PRICE เพื่อคุมความเสี่ยงตาม RISK_PERCENTAGE)"
+
+	stopLoss := 0.0
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--stop" {
+			positional = append(positional, args[i])
+			continue
+		}
+		if i+1 >= len(args) {
+			return errors.New(usage)
+		}
+		value, err := strconv.ParseFloat(args[i+1], 64)
+		if err != nil || value <= 0 {
+			return fmt.Errorf("stop loss ไม่ถูกต้อง: %s", args[i+1])
+		}
+		stopLoss = value
+		i++
+	}
+	args = positional
+
+	if len(args) < 5 {
+		return errors.New(usage)
+	}
+
+	algo := args[0]
+	if algo != "twap" && algo != "iceberg" {
+		return errors.New(usage)
+	}
+	symbol := strings.ToUpper(args[1])
+	side := strings.ToUpper(args[2])
+	if side != "BUY" && side != "SELL" {
+		return fmt.Errorf("side ต้องเป็น BUY หรือ SELL: %s", args[2])
+	}
+	quantity, err := strconv.ParseFloat(args[3], 64)
+	if err != nil || quantity <= 0 {
+		return fmt.Errorf("จำนวนไม่ถูกต้อง: %s", args[3])
+	}
+
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+	if err := reconcileOnStartup(client); err != nil {
+		return err
+	}
+
+	executor, err := newExecutor(client, algo, symbol, side, quantity)
+	if err != nil {
+		return err
+	}
+	executor.stopLoss = stopLoss
+	executor.stop = make(chan os.Signal, 1)
+	signal.Notify(executor.stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(executor.stop)
+
+	switch algo {
+	case "twap":
+		duration, err := time.ParseDuration(args[4])
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("ระยะเวลาไม่ถูกต้อง: %s", args[4])
+		}
+		slices := int(duration / time.Minute)
+		if len(args) > 5 {
+			if slices, err = strconv.Atoi(args[5]); err != nil || slices <= 0 {
+				return fmt.Errorf("จำนวนส่วนไม่ถูกต้อง: %s", args[5])
+			}
+		}
+		executor.runTWAP(duration, max(slices, 1))
+
+	case "iceberg":
+		if len(args) < 6 {
+			return errors.New(usage)
+		}
+		visible, err := strconv.ParseFloat(args[4], 64)
+		if err != nil || visible <= 0 {
+			return fmt.Errorf("จำนวนที่แสดงไม่ถูกต้อง: %s", args[4])
+		}
+		price, err := strconv.ParseFloat(args[5], 64)
+		if err != nil || price <= 0 {
+			return fmt.Errorf("ราคาไม่ถูกต้อง: %s", args[5])
+		}
+		var deadline time.Time
+		if len(args) > 6 {
+			duration, err := time.ParseDuration(args[6])
+			if err != nil {
+				return fmt.Errorf("ระยะเวลาไม่ถูกต้อง: %s", args[6])
+			}
+			deadline = time.Now().Add(duration)
+		}
+		executor.runIceberg(visible, price, deadline)
+
+	default:
+		return errors.New(usage)
+	}
+
+	printExecutionReport(executor.finish())
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTWAPSlicing(t *testing.T) {
+	tests := []struct {
+		name          string
+		requested     float64
+		slices        int
+		participation float64
+		volumes       []float64 // market volume seen before each slice
+		want          []float64
+	}{
+		{
+			name:      "equal slices without a cap",
+			requested: 100,
+			slices:    4,
+			volumes:   []float64{0, 0, 0, 0},
+			want:      []float64{25, 25, 25, 25},
+		},
+		{
+			name:          "capped slices catch up later",
+			requested:     100,
+			slices:        4,
+			participation: 0.1,
+			volumes:       []float64{100, 1000, 1000, 1000},
+			want:          []float64{10, 30, 30, 30},
+		},
+		{
+			name:          "thin market leaves a remainder",
+			requested:     100,
+			slices:        2,
+			participation: 0.1,
+			volumes:       []float64{100, 200},
+			want:          []float64{10, 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining := tt.requested
+			var got []float64
+			for i := 0; i < tt.slices; i++ {
+				quantity := capToParticipation(twapSliceTarget(remaining, tt.slices, i), tt.volumes[i], tt.participation)
+				got = append(got, quantity)
+				remaining -= quantity
+			}
+			if !floatsEqual(got, tt.want) {
+				t.Errorf("slices = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVolumeSince(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	klines := []Kline{
+		{CloseTime: now.Add(-3 * time.Minute).UnixMilli(), Volume: 5},
+		{CloseTime: now.Add(-2 * time.Minute).UnixMilli(), Volume: 7},
+		{CloseTime: now.Add(-time.Minute).UnixMilli(), Volume: 11},
+	}
+	if got := volumeSince(klines, now.Add(-2*time.Minute)); got != 18 {
+		t.Errorf("volumeSince = %v, want 18", got)
+	}
+}
+
+func TestExecutorTradable(t *testing.T) {
+	e := &Executor{rules: &SymbolRules{StepSize: 0.1, MinQty: 1, MinNotional: 5, stepDecimals: 1}}
+
+	tests := []struct {
+		name     string
+		quantity float64
+		price    float64
+		want     string
+	}{
+		{"floored to the step", 12.37, 1, "12.3"},
+		{"iceberg clip of the visible size", math.Min(5, 120), 2, "5.0"},
+		{"below the minimum quantity", 0.95, 100, ""},
+		{"below the minimum notional", 4, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.tradable(tt.quantity, tt.price); got != tt.want {
+				t.Errorf("tradable(%v, %v) = %q, want %q", tt.quantity, tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutionReportSummarize(t *testing.T) {
+	fills := []ExecutionFill{{Quantity: 10, Quote: 101}, {Quantity: 10, Quote: 103}}
+
+	tests := []struct {
+		name    string
+		report  ExecutionReport
+		wantAvg float64
+		wantBps float64
+	}{
+		{
+			name:    "buy above arrival is positive",
+			report:  ExecutionReport{Side: "BUY", ArrivalPrice: 10, Filled: 20, Fills: fills},
+			wantAvg: 10.2,
+			wantBps: 200,
+		},
+		{
+			name:    "sell below arrival is positive",
+			report:  ExecutionReport{Side: "SELL", ArrivalPrice: 10.4, Filled: 20, Fills: fills},
+			wantAvg: 10.2,
+			wantBps: 0.2 / 10.4 * 10000,
+		},
+		{
+			name:    "sell above arrival is negative",
+			report:  ExecutionReport{Side: "SELL", ArrivalPrice: 10, Filled: 20, Fills: fills},
+			wantAvg: 10.2,
+			wantBps: -200,
+		},
+		{
+			name:   "nothing filled",
+			report: ExecutionReport{Side: "BUY", ArrivalPrice: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.report
+			r.summarize()
+			if math.Abs(r.AvgPrice-tt.wantAvg) > 1e-9 || math.Abs(r.SlippageBps-tt.wantBps) > 1e-9 {
+				t.Errorf("avg/bps = %v/%v, want %v/%v", r.AvgPrice, r.SlippageBps, tt.wantAvg, tt.wantBps)
+			}
+		})
+	}
+}
+
+func TestRunExecCommandRejectsUnknownAlgo(t *testing.T) {
+	// Fails before any client, reconcile or price request is made
+	if err := runExecCommand([]string{"vwap", "AAAUSDT", "BUY", "1", "1m"}); err == nil {
+		t.Error("expected a usage error for an unknown algo")
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	feesFile = "fees.json"
+	// Cached commission rates are refreshed after this long
+	feeCacheTTL = 24 * time.Hour
+	// Wait before asking Binance again after the rates could not be fetched
+	feeRetryInterval = time.Hour
+	// Binance's spot discount when fees are paid in BNB
+	bnbFeeDiscount = 0.25
+	// Spot rates for a regular account, used when the account cannot be queried
+	defaultFeeRate = 0.001
+)
+
+// FeeSchedule represents the account's trading fees
+type FeeSchedule struct {
+	Maker     float64   `json:"maker"` // fraction of notional, before the BNB discount
+	Taker     float64   `json:"taker"`
+	BNBBurn   bool      `json:"bnbBurn"`
+	Source    string    `json:"source"` // "account" or "default"
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+var (
+	feeScheduleMu sync.Mutex
+	feeSchedule   FeeSchedule
+	// No fetch is attempted before this after a failure
+	feeScheduleRetryAt time.Time
+)
+
+// EffectiveMaker returns the maker rate after the BNB discount
+func (f FeeSchedule) EffectiveMaker() float64 {
+	if f.BNBBurn {
+		return f.Maker * (1 - bnbFeeDiscount)
+	}
+	return f.Maker
+}
+
+// EffectiveTaker returns the taker rate after the BNB discount
+func (f FeeSchedule) EffectiveTaker() float64 {
+	if f.BNBBurn {
+		return f.Taker * (1 - bnbFeeDiscount)
+	}
+	return f.Taker
+}
+
+// breakEvenPrice returns the exit price that recovers the entry price plus both fees
+func breakEvenPrice(entry, buyFee, sellFee float64) float64 {
+	return entry * (1 + buyFee) / (1 - sellFee)
+}
+
+// netReturnPercent returns the percent gained between entry and exit after both fees
+func netReturnPercent(entry, exit, buyFee, sellFee float64) float64 {
+	if entry <= 0 {
+		return 0
+	}
+	return (exit*(1-sellFee)/(entry*(1+buyFee)) - 1) * 100
+}
+
+// fetchFeeSchedule loads the account's commission rates and BNB-burn setting from Binance
+func fetchFeeSchedule(client *BinanceClient) (FeeSchedule, error) {
+	maker, taker, err := getCommissionRates(client)
+	if err != nil {
+		return FeeSchedule{}, fmt.Errorf("ไม่สามารถดึงค่าธรรมเนียม: %v", err)
+	}
+	burn, err := getBNBBurn(client)
+	if err != nil {
+		return FeeSchedule{}, fmt.Errorf("ไม่สามารถดึงการตั้งค่า BNB burn: %v", err)
+	}
+	return FeeSchedule{Maker: maker, Taker: taker, BNBBurn: burn, Source: "account", FetchedAt: time.Now()}, nil
+}
+
+// currentFeeSchedule returns the account's fees, cached in state for a day and refreshed once the
+// cache is older than that, so a long-running daemon follows rate changes; defaults apply without API keys
+func currentFeeSchedule() FeeSchedule {
+	feeScheduleMu.Lock()
+	defer feeScheduleMu.Unlock()
+
+	now := time.Now()
+	if feeSchedule.Source == "account" && now.Sub(feeSchedule.FetchedAt) < feeCacheTTL {
+		return feeSchedule
+	}
+	if now.Before(feeScheduleRetryAt) {
+		return feeSchedule
+	}
+
+	var cached FeeSchedule
+	if found, err := loadState(feesFile, &cached); err == nil && found && cached.Source == "account" {
+		if now.Sub(cached.FetchedAt) < feeCacheTTL {
+			feeSchedule = cached
+			return feeSchedule
+		}
+		if feeSchedule.Source != "account" {
+			feeSchedule = cached
+		}
+	}
+
+	// Expired account rates stay closer to the truth than the defaults until a fetch succeeds
+	if feeSchedule.Source != "account" {
+		feeSchedule = FeeSchedule{Maker: defaultFeeRate, Taker: defaultFeeRate, Source: "default"}
+	}
+	client, err := newClientFromEnv()
+	if err != nil {
+		feeScheduleRetryAt = now.Add(feeRetryInterval)
+		return feeSchedule
+	}
+	fetched, err := fetchFeeSchedule(client)
+	if err != nil {
+		feeScheduleRetryAt = now.Add(feeRetryInterval)
+		fmt.Printf("⚠️ %v - ใช้ค่าธรรมเนียม %s\n", err, describeFees(feeSchedule))
+		return feeSchedule
+	}
+	feeSchedule = fetched
+	if err := saveState(feesFile, fetched); err != nil {
+		fmt.Printf("⚠️ ไม่สามารถบันทึกค่าธรรมเนียม: %v\n", err)
+	}
+	return feeSchedule
+}
+
+// describeFees summarizes the schedule for console output
+func describeFees(f FeeSchedule) string {
+	burn := "ไม่ใช้ BNB"
+	if f.BNBBurn {
+		burn = fmt.Sprintf("จ่ายด้วย BNB ลด %.0f%%", bnbFeeDiscount*100)
+	}
+	source := "จากบัญชี"
+	if f.Source != "account" {
+		source = "ค่ามาตรฐาน"
+	}
+	return fmt.Sprintf("maker %.3f%% / taker %.3f%% (%s, %s)", f.EffectiveMaker()*100, f.EffectiveTaker()*100, burn, source)
+}
+
+// applyFeeTargets adds break-even and gross/net target returns to an analysis;
+// entries are assumed to be market buys (taker) and targets resting sells (maker)
+func applyFeeTargets(analysis *AINewCoinAnalysis, fees FeeSchedule) {
+	buyFee, sellFee := fees.EffectiveTaker(), fees.EffectiveMaker()
+
+	analysis.BreakEven = breakEvenPrice(analysis.Price, buyFee, sellFee)
+	analysis.ProfitTargetGrossPct = make([]float64, len(analysis.ProfitTarget))
+	analysis.ProfitTargetNetPct = make([]float64, len(analysis.ProfitTarget))
+	for i, target := range analysis.ProfitTarget {
+		if analysis.Price > 0 {
+			analysis.ProfitTargetGrossPct[i] = (target/analysis.Price - 1) * 100
+		}
+		analysis.ProfitTargetNetPct[i] = netReturnPercent(analysis.Price, target, buyFee, sellFee)
+	}
+}
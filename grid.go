@@ -1,5 +1,544 @@
-//go:build ignore
-
-// Grid trading system removed. This file is ignored during build.
 package main
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const gridPollInterval = 15 * time.Second
+
+// GridOrder represents a live order owned by a grid
+type GridOrder struct {
+	OrderID    string    `json:"orderId"`
+	Level      int       `json:"level"`
+	Side       string    `json:"side"`
+	Price      float64   `json:"price"`
+	Quantity   float64   `json:"quantity"`
+	EntryPrice float64   `json:"entryPrice"` // price of the fill this order closes (0 = opening order)
+	PlacedAt   time.Time `json:"placedAt"`
+}
+
+// GridPending represents a counter-order that could not be placed yet; Sync retries it
+type GridPending struct {
+	Level      int       `json:"level"`
+	Side       string    `json:"side"`
+	EntryPrice float64   `json:"entryPrice"`
+	Key        string    `json:"key"` // same client order ID on every retry
+	LastError  string    `json:"lastError"`
+	Since      time.Time `json:"since"`
+}
+
+// GridState represents everything a grid needs to resume after a restart
+type GridState struct {
+	Config          GridConfig    `json:"config"`
+	Levels          []float64     `json:"levels"`
+	Quantity        float64       `json:"quantity"` // base quantity per grid line
+	Orders          []GridOrder   `json:"orders"`
+	Pending         []GridPending `json:"pending,omitempty"` // counter-orders waiting for a retry, one per level
+	RealizedProfit  float64       `json:"realizedProfit"`
+	CompletedTrades int           `json:"completedTrades"`
+	Running         bool          `json:"running"`
+	StartedAt       time.Time     `json:"startedAt"` // keys the start-up orders' client IDs
+	UpdatedAt       time.Time     `json:"updatedAt"`
+}
+
+// GridEngine places and maintains grid orders for one symbol
+type GridEngine struct {
+	client *BinanceClient
+	rules  *SymbolRules
+	state  GridState
+}
+
+// calculateGridLevels builds GridCount+1 price levels between LowerPrice and UpperPrice
+func calculateGridLevels(config GridConfig) ([]float64, error) {
+	if config.GridCount < 2 {
+		return nil, fmt.Errorf("จำนวนกริดต้องมีอย่างน้อย 2")
+	}
+	if config.LowerPrice <= 0 || config.UpperPrice <= config.LowerPrice {
+		return nil, fmt.Errorf("ช่วงราคากริดไม่ถูกต้อง: %.8f - %.8f", config.LowerPrice, config.UpperPrice)
+	}
+
+	levels := make([]float64, config.GridCount+1)
+	switch config.Mode {
+	case "geometric":
+		ratio := math.Pow(config.UpperPrice/config.LowerPrice, 1/float64(config.GridCount))
+		for i := range levels {
+			levels[i] = config.LowerPrice * math.Pow(ratio, float64(i))
+		}
+	case "", "arithmetic":
+		step := (config.UpperPrice - config.LowerPrice) / float64(config.GridCount)
+		for i := range levels {
+			levels[i] = config.LowerPrice + step*float64(i)
+		}
+	default:
+		return nil, fmt.Errorf("ไม่รู้จักโหมดกริด %q", config.Mode)
+	}
+
+	return levels, nil
+}
+
+// Grid state file name for a symbol
+func gridStateFile(symbol string) string {
+	return "grid_" + symbol + ".json"
+}
+
+// newGridEngine prepares a new grid from config; call Start to place its orders
+func newGridEngine(client *BinanceClient, config GridConfig) (*GridEngine, error) {
+	var existing GridState
+	found, err := loadState(gridStateFile(config.Symbol), &existing)
+	if err != nil {
+		return nil, err
+	}
+	if found && existing.Running {
+		return nil, fmt.Errorf("กริด %s กำลังทำงานอยู่ ใช้ 'grid stop %s' ก่อน", config.Symbol, config.Symbol)
+	}
+
+	levels, err := calculateGridLevels(config)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := getSymbolRules(config.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.CreatedAt.IsZero() {
+		config.CreatedAt = time.Now()
+	}
+
+	return &GridEngine{
+		client: client,
+		rules:  rules,
+		state: GridState{
+			Config: config,
+			Levels: levels,
+		},
+	}, nil
+}
+
+// loadGridEngine resumes a grid from its saved state
+func loadGridEngine(client *BinanceClient, symbol string) (*GridEngine, error) {
+	var state GridState
+	found, err := loadState(gridStateFile(symbol), &state)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("ไม่พบกริดสำหรับ %s", symbol)
+	}
+
+	rules, err := getSymbolRules(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GridEngine{client: client, rules: rules, state: state}, nil
+}
+
+// save persists the grid state
+func (g *GridEngine) save() error {
+	g.state.UpdatedAt = time.Now()
+	return saveState(gridStateFile(g.state.Config.Symbol), g.state)
+}
+
+// Start places the initial buy orders below the current price and sell orders above it
+func (g *GridEngine) Start() error {
+	config := g.state.Config
+
+	currentPrice, err := getCurrentPriceForSymbol(g.client, config.Symbol)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึงราคา %s: %v", config.Symbol, err)
+	}
+	if currentPrice < config.LowerPrice || currentPrice > config.UpperPrice {
+		return fmt.Errorf("ราคาปัจจุบัน $%.8f อยู่นอกช่วงกริด", currentPrice)
+	}
+
+	g.state.Quantity = floorToStep(config.Investment/float64(config.GridCount)/currentPrice, g.rules.StepSize)
+	if g.state.Quantity < g.rules.MinQty || g.state.Quantity*config.LowerPrice < g.rules.MinNotional {
+		return fmt.Errorf("เงินลงทุนต่อกริดต่ำกว่าขั้นต่ำของ %s", config.Symbol)
+	}
+
+	// Leave the level nearest to the current price empty so every fill has a free slot
+	nearest := 0
+	for i, level := range g.state.Levels {
+		if math.Abs(level-currentPrice) < math.Abs(g.state.Levels[nearest]-currentPrice) {
+			nearest = i
+		}
+	}
+
+	g.state.StartedAt = time.Now()
+	sellCount := len(g.state.Levels) - 1 - nearest
+	if err := g.ensureBaseInventory(float64(sellCount) * g.state.Quantity); err != nil {
+		return err
+	}
+
+	g.state.Running = true
+	if err := g.save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("🕸️ เริ่มกริด %s: %d ระดับ, %s ต่อกริด, ราคาปัจจุบัน $%.8f\n",
+		config.Symbol, len(g.state.Levels), g.rules.formatQuantity(g.state.Quantity), currentPrice)
+
+	for i := range g.state.Levels {
+		var err error
+		if i < nearest {
+			err = g.placeLevelOrder(i, "BUY", 0, g.startKey(i))
+		} else if i > nearest {
+			err = g.placeLevelOrder(i, "SELL", currentPrice, g.startKey(i))
+		}
+		if err != nil {
+			fmt.Printf("❌ ไม่สามารถวาง order ระดับ %d: %v\n", i, err)
+		}
+	}
+
+	return g.save()
+}
+
+// ensureBaseInventory market-buys whatever base asset the initial sell orders still need
+func (g *GridEngine) ensureBaseInventory(required float64) error {
+	if required <= 0 {
+		return nil
+	}
+
+	balances, err := getBalances(g.client)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึงยอดเงิน: %v", err)
+	}
+
+	shortfall := required - balances[getBaseCoin(g.state.Config.Symbol)]
+	if shortfall <= 0 {
+		return nil
+	}
+
+	// Round up so the purchased amount covers every sell order
+	quantity := floorToStep(shortfall, g.rules.StepSize) + g.rules.StepSize
+	fmt.Printf("🛒 ซื้อ %s %s เพื่อเตรียมวาง sell orders ของกริด\n", g.rules.formatQuantity(quantity), getBaseCoin(g.state.Config.Symbol))
+
+	tag := OrderTag{Strategy: strategyGrid, Key: g.startKey(-1)}
+	if _, err := placeOrder(g.client, tag, g.state.Config.Symbol, "BUY", "MARKET", g.rules.formatQuantity(quantity), ""); err != nil {
+		return fmt.Errorf("ไม่สามารถซื้อเหรียญสำหรับกริด: %v", err)
+	}
+	return nil
+}
+
+// startKey names an order placed by Start; level -1 is the inventory purchase
+func (g *GridEngine) startKey(level int) string {
+	return fmt.Sprintf("start|%d|%d", g.state.StartedAt.UnixNano(), level)
+}
+
+// placeLevelOrder places a limit order at a grid level and starts tracking it; key names the
+// decision behind it so a retried placement reuses the client order ID
+func (g *GridEngine) placeLevelOrder(level int, side string, entryPrice float64, key string) error {
+	if level < 0 || level >= len(g.state.Levels) {
+		return fmt.Errorf("ระดับ %d อยู่นอกกริด", level)
+	}
+
+	price := g.state.Levels[level]
+	orderID, err := placeOrder(g.client, OrderTag{Strategy: strategyGrid, Key: key}, g.state.Config.Symbol, side, "LIMIT",
+		g.rules.formatQuantity(g.state.Quantity), g.rules.formatPrice(price))
+	if err != nil {
+		return err
+	}
+
+	g.state.Orders = append(g.state.Orders, GridOrder{
+		OrderID:    orderID,
+		Level:      level,
+		Side:       side,
+		Price:      price,
+		Quantity:   g.state.Quantity,
+		EntryPrice: entryPrice,
+		PlacedAt:   time.Now(),
+	})
+
+	fmt.Printf("   📌 %s ระดับ %d @ $%s (order %s)\n", side, level, g.rules.formatPrice(price), orderID)
+	return g.save()
+}
+
+// Sync checks every tracked order and answers each fill with the opposite order one level away
+func (g *GridEngine) Sync() error {
+	if !g.state.Running {
+		return nil
+	}
+
+	g.retryPending()
+
+	for _, order := range append([]GridOrder(nil), g.state.Orders...) {
+		status, err := getOrder(g.client, g.state.Config.Symbol, order.OrderID)
+		if err != nil {
+			// Unchecked orders stay tracked and are retried next cycle
+			g.save()
+			return fmt.Errorf("ไม่สามารถตรวจสอบ order %s: %v", order.OrderID, err)
+		}
+
+		switch status.Status {
+		case "FILLED":
+			g.removeOrder(order.OrderID)
+			g.handleFill(order)
+		case "CANCELED", "EXPIRED", "REJECTED":
+			g.removeOrder(order.OrderID)
+			fmt.Printf("⚠️ order %s ระดับ %d ถูก %s นอกกริด - เลิกติดตาม\n", order.OrderID, order.Level, status.Status)
+		}
+	}
+
+	return g.save()
+}
+
+// removeOrder stops tracking an order
+func (g *GridEngine) removeOrder(orderID string) {
+	kept := g.state.Orders[:0]
+	for _, order := range g.state.Orders {
+		if order.OrderID != orderID {
+			kept = append(kept, order)
+		}
+	}
+	g.state.Orders = kept
+}
+
+// bookFill records a filled grid order; only a SELL that closes a BUY completes a round trip,
+// and that round trip pays the maker fee on both of its legs
+func (s *GridState) bookFill(order GridOrder, makerFee float64) (float64, bool) {
+	if order.Side != "SELL" || order.EntryPrice <= 0 {
+		return 0, false
+	}
+
+	profit := order.Quantity * (order.Price - order.EntryPrice)
+	profit -= order.Quantity * (order.Price + order.EntryPrice) * makerFee
+	s.RealizedProfit += profit
+	s.CompletedTrades++
+	return profit, true
+}
+
+// handleFill books the profit of a completed round trip and places the opposite order
+func (g *GridEngine) handleFill(order GridOrder) {
+	fmt.Printf("✅ %s ระดับ %d @ $%s ได้รับการจับคู่แล้ว\n", order.Side, order.Level, g.rules.formatPrice(order.Price))
+
+	if profit, closed := g.state.bookFill(order, currentFeeSchedule().EffectiveMaker()); closed {
+		fmt.Printf("   💰 กำไรกริด %+.4f USDT (รวม %.4f USDT, %d รอบ)\n", profit, g.state.RealizedProfit, g.state.CompletedTrades)
+	}
+
+	counter := GridPending{Level: order.Level - 1, Side: "BUY", Key: "fill|" + order.OrderID} // a re-entry opens a new round trip
+	if order.Side == "BUY" {
+		counter = GridPending{Level: order.Level + 1, Side: "SELL", EntryPrice: order.Price, Key: "fill|" + order.OrderID}
+	}
+	if counter.Level < 0 || counter.Level >= len(g.state.Levels) {
+		return
+	}
+	if err := g.placeLevelOrder(counter.Level, counter.Side, counter.EntryPrice, counter.Key); err != nil {
+		fmt.Printf("❌ ไม่สามารถวาง order ฝั่งตรงข้ามของระดับ %d: %v - จะลองใหม่รอบถัดไป\n", order.Level, err)
+		counter.LastError = err.Error()
+		counter.Since = time.Now()
+		g.state.setPending(counter)
+	}
+}
+
+// setPending queues a counter-order, replacing any earlier one for the same level
+func (s *GridState) setPending(pending GridPending) {
+	s.clearPending(pending.Level)
+	s.Pending = append(s.Pending, pending)
+}
+
+// clearPending drops the queued counter-order of a level
+func (s *GridState) clearPending(level int) {
+	kept := s.Pending[:0]
+	for _, pending := range s.Pending {
+		if pending.Level != level {
+			kept = append(kept, pending)
+		}
+	}
+	s.Pending = kept
+}
+
+// retryPending places queued counter-orders; failures stay queued for the next Sync
+func (g *GridEngine) retryPending() {
+	for _, pending := range append([]GridPending(nil), g.state.Pending...) {
+		if err := g.placeLevelOrder(pending.Level, pending.Side, pending.EntryPrice, pending.Key); err != nil {
+			fmt.Printf("⚠️ ยังวาง %s ระดับ %d ไม่ได้: %v\n", pending.Side, pending.Level, err)
+			pending.LastError = err.Error()
+			g.state.setPending(pending)
+			continue
+		}
+		g.state.clearPending(pending.Level)
+	}
+}
+
+// Stop cancels every order the grid owns and marks it as stopped
+func (g *GridEngine) Stop() error {
+	fmt.Printf("🛑 กำลังหยุดกริด %s และยกเลิก %d orders...\n", g.state.Config.Symbol, len(g.state.Orders))
+
+	var remaining []GridOrder
+	for _, order := range g.state.Orders {
+		if err := cancelOrder(g.client, g.state.Config.Symbol, order.OrderID); err != nil {
+			// Already filled or cancelled orders are no longer ours to cancel
+			if !strings.Contains(err.Error(), "Unknown order") {
+				fmt.Printf("❌ ไม่สามารถยกเลิก order %s: %v\n", order.OrderID, err)
+				remaining = append(remaining, order)
+				continue
+			}
+		}
+		fmt.Printf("   🗑️ ยกเลิก order %s แล้ว\n", order.OrderID)
+	}
+
+	g.state.Orders = remaining
+	g.state.Pending = nil
+	if len(remaining) > 0 {
+		g.save()
+		return fmt.Errorf("ยกเลิกไม่สำเร็จ %d orders ลองใหม่อีกครั้ง", len(remaining))
+	}
+
+	g.state.Running = false
+	return g.save()
+}
+
+// printStatus prints the grid configuration, live orders and realized profit
+func (g *GridEngine) printStatus() {
+	config := g.state.Config
+	mode := config.Mode
+	if mode == "" {
+		mode = "arithmetic"
+	}
+
+	fmt.Printf("🕸️ กริด %s (%s)\n", config.Symbol, mode)
+	fmt.Printf("   • ช่วงราคา: $%.8f - $%.8f (%d กริด)\n", config.LowerPrice, config.UpperPrice, config.GridCount)
+	fmt.Printf("   • เงินลงทุน: %.2f USDT, %s ต่อกริด\n", config.Investment, g.rules.formatQuantity(g.state.Quantity))
+	fmt.Printf("   • สถานะ: %s\n", map[bool]string{true: "ทำงาน", false: "หยุด"}[g.state.Running])
+	fmt.Printf("   • orders ที่เปิดอยู่: %d\n", len(g.state.Orders))
+	for _, pending := range g.state.Pending {
+		fmt.Printf("   • รอวางใหม่: %s ระดับ %d ตั้งแต่ %s (%s)\n", pending.Side, pending.Level, pending.Since.Format("2006-01-02 15:04"), pending.LastError)
+	}
+	fmt.Printf("   • กำไรกริดสะสม: %.4f USDT (%d รอบ)\n", g.state.RealizedProfit, g.state.CompletedTrades)
+}
+
+// Run reacts to fills from the user data stream (with polling as a fallback) until SIGINT/SIGTERM;
+// open orders stay on the book for the next run
+func (g *GridEngine) Run() error {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	stream := newUserDataStream(g.client)
+	stream.Watch(g.state.Config.Symbol)
+	stream.Start()
+	defer stream.Close()
+
+	ticker := time.NewTicker(gridPollInterval)
+	defer ticker.Stop()
+
+	fmt.Printf("🔄 กริด %s ทำงานอยู่ (Ctrl+C เพื่อออก, 'grid stop %s' เพื่อยกเลิก orders)\n",
+		g.state.Config.Symbol, g.state.Config.Symbol)
+
+	for {
+		if err := g.Sync(); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+		}
+
+		if !g.waitForActivity(stop, stream, ticker) {
+			fmt.Println("\n💾 บันทึกสถานะกริดแล้ว orders ยังคงเปิดอยู่")
+			return g.save()
+		}
+	}
+}
+
+// waitForActivity blocks until a fill on the grid's symbol or the next poll; it reports false on shutdown
+func (g *GridEngine) waitForActivity(stop <-chan os.Signal, stream *UserDataStream, ticker *time.Ticker) bool {
+	for {
+		select {
+		case <-stop:
+			return false
+		case fill := <-stream.Fills:
+			if fill.Symbol == g.state.Config.Symbol {
+				return true
+			}
+		case <-stream.Balances:
+		case <-ticker.C:
+			return true
+		}
+	}
+}
+
+// runGridCommand handles: grid start|run|stop|status
+func runGridCommand(args []string) error {
+	usage := "ใช้งาน: grid start SYMBOL LOWER UPPER COUNT INVESTMENT [arithmetic|geometric] | grid run SYMBOL | grid stop SYMBOL | grid status SYMBOL"
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	symbol := strings.ToUpper(args[1])
+
+	if args[0] == "start" || args[0] == "run" {
+		if err := reconcileOnStartup(client); err != nil {
+			return err
+		}
+	}
+
+	switch args[0] {
+	case "start":
+		if len(args) < 6 {
+			return errors.New(usage)
+		}
+		lower, err1 := strconv.ParseFloat(args[2], 64)
+		upper, err2 := strconv.ParseFloat(args[3], 64)
+		count, err3 := strconv.Atoi(args[4])
+		investment, err4 := strconv.ParseFloat(args[5], 64)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			return fmt.Errorf("พารามิเตอร์กริดไม่ถูกต้อง\n%s", usage)
+		}
+
+		config := GridConfig{
+			Symbol:     symbol,
+			GridCount:  count,
+			LowerPrice: lower,
+			UpperPrice: upper,
+			Investment: investment,
+		}
+		if len(args) > 6 {
+			config.Mode = args[6]
+		}
+
+		grid, err := newGridEngine(client, config)
+		if err != nil {
+			return err
+		}
+		if err := grid.Start(); err != nil {
+			return err
+		}
+		return grid.Run()
+
+	case "run", "stop", "status":
+		grid, err := loadGridEngine(client, symbol)
+		if err != nil {
+			return err
+		}
+		switch args[0] {
+		case "run":
+			if !grid.state.Running {
+				return fmt.Errorf("กริด %s ถูกหยุดแล้ว", symbol)
+			}
+			return grid.Run()
+		case "stop":
+			if err := grid.Stop(); err != nil {
+				return err
+			}
+			grid.printStatus()
+			return nil
+		default:
+			grid.printStatus()
+			return nil
+		}
+	}
+
+	return errors.New(usage)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateGridLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  GridConfig
+		want    []float64
+		wantErr bool
+	}{
+		{
+			name:   "arithmetic",
+			config: GridConfig{LowerPrice: 1, UpperPrice: 2, GridCount: 4},
+			want:   []float64{1, 1.25, 1.5, 1.75, 2},
+		},
+		{
+			name:   "explicit arithmetic",
+			config: GridConfig{LowerPrice: 10, UpperPrice: 20, GridCount: 2, Mode: "arithmetic"},
+			want:   []float64{10, 15, 20},
+		},
+		{
+			name:   "geometric",
+			config: GridConfig{LowerPrice: 1, UpperPrice: 8, GridCount: 3, Mode: "geometric"},
+			want:   []float64{1, 2, 4, 8},
+		},
+		{name: "too few grids", config: GridConfig{LowerPrice: 1, UpperPrice: 2, GridCount: 1}, wantErr: true},
+		{name: "inverted range", config: GridConfig{LowerPrice: 2, UpperPrice: 1, GridCount: 4}, wantErr: true},
+		{name: "zero lower", config: GridConfig{LowerPrice: 0, UpperPrice: 1, GridCount: 4}, wantErr: true},
+		{name: "unknown mode", config: GridConfig{LowerPrice: 1, UpperPrice: 2, GridCount: 4, Mode: "fib"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calculateGridLevels(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("levels = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("level %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGridBookFill(t *testing.T) {
+	const fee = 0.001
+
+	tests := []struct {
+		name       string
+		fills      []GridOrder
+		wantProfit float64
+		wantTrades int
+	}{
+		{
+			name:  "opening buy books nothing",
+			fills: []GridOrder{{Side: "BUY", Price: 1, Quantity: 10}},
+		},
+		{
+			name: "buy then sell books one spacing",
+			fills: []GridOrder{
+				{Side: "BUY", Price: 1, Quantity: 10},
+				{Side: "SELL", Price: 1.1, Quantity: 10, EntryPrice: 1},
+			},
+			wantProfit: 10*0.1 - 10*(1.1+1)*fee,
+			wantTrades: 1,
+		},
+		{
+			name: "buy sell buy sell books two spacings",
+			fills: []GridOrder{
+				{Side: "BUY", Price: 1, Quantity: 10},
+				{Side: "SELL", Price: 1.1, Quantity: 10, EntryPrice: 1},
+				{Side: "BUY", Price: 1, Quantity: 10},
+				{Side: "SELL", Price: 1.1, Quantity: 10, EntryPrice: 1},
+			},
+			wantProfit: 2 * (10*0.1 - 10*(1.1+1)*fee),
+			wantTrades: 2,
+		},
+		{
+			name:  "re-entry buy from an old state file books nothing",
+			fills: []GridOrder{{Side: "BUY", Price: 1, Quantity: 10, EntryPrice: 1.1}},
+		},
+		{
+			name:       "initial sell closes inventory bought at the start price",
+			fills:      []GridOrder{{Side: "SELL", Price: 1.2, Quantity: 5, EntryPrice: 1.05}},
+			wantProfit: 5*0.15 - 5*(1.2+1.05)*fee,
+			wantTrades: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state GridState
+			for _, fill := range tt.fills {
+				state.bookFill(fill, fee)
+			}
+			if math.Abs(state.RealizedProfit-tt.wantProfit) > 1e-9 {
+				t.Errorf("RealizedProfit = %v, want %v", state.RealizedProfit, tt.wantProfit)
+			}
+			if state.CompletedTrades != tt.wantTrades {
+				t.Errorf("CompletedTrades = %d, want %d", state.CompletedTrades, tt.wantTrades)
+			}
+		})
+	}
+}
+
+func TestGridPending(t *testing.T) {
+	var state GridState
+	state.setPending(GridPending{Level: 3, Side: "SELL", Key: "fill|1", LastError: "risk"})
+	state.setPending(GridPending{Level: 1, Side: "BUY", Key: "fill|2"})
+	state.setPending(GridPending{Level: 3, Side: "SELL", Key: "fill|1", LastError: "network"})
+
+	if len(state.Pending) != 2 {
+		t.Fatalf("pending = %+v, want one entry per level", state.Pending)
+	}
+	for _, pending := range state.Pending {
+		if pending.Level == 3 && pending.LastError != "network" {
+			t.Errorf("level 3 error = %q, want the latest", pending.LastError)
+		}
+	}
+
+	state.clearPending(3)
+	if len(state.Pending) != 1 || state.Pending[0].Level != 1 {
+		t.Errorf("pending after clear = %+v, want only level 1", state.Pending)
+	}
+}
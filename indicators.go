@@ -0,0 +1,252 @@
+package main
+
+import (
+	"math"
+)
+
+// IndicatorConfig represents indicator periods loaded from .env
+type IndicatorConfig struct {
+	RSIPeriod  int `json:"rsiPeriod"`  // RSI_PERIOD, also used for ATR and stochastic RSI
+	SMAShort   int `json:"smaShort"`   // SMA_SHORT, also the short EMA
+	SMALong    int `json:"smaLong"`    // SMA_LONG, also the long EMA and Bollinger period
+	MACDFast   int `json:"macdFast"`   // MACD_FAST
+	MACDSlow   int `json:"macdSlow"`   // MACD_SLOW
+	MACDSignal int `json:"macdSignal"` // MACD_SIGNAL
+}
+
+// Bollinger band width and stochastic RSI smoothing are fixed at their conventional values
+const (
+	bollingerStdDev = 2.0
+	stochRSISmoothK = 3
+	stochRSISmoothD = 3
+)
+
+// loadIndicatorConfig reads indicator periods, using the .env.example defaults when unset or below 1
+func loadIndicatorConfig() IndicatorConfig {
+	return IndicatorConfig{
+		RSIPeriod:  envPeriod("RSI_PERIOD", 14),
+		SMAShort:   envPeriod("SMA_SHORT", 10),
+		SMALong:    envPeriod("SMA_LONG", 20),
+		MACDFast:   envPeriod("MACD_FAST", 12),
+		MACDSlow:   envPeriod("MACD_SLOW", 26),
+		MACDSignal: envPeriod("MACD_SIGNAL", 9),
+	}
+}
+
+// calculateEMA returns the exponential moving average seeded with the first SMA;
+// like calculateSMA the result starts at index period-1 of the input
+func calculateEMA(values []float64, period int) []float64 {
+	if period <= 0 || len(values) < period {
+		return []float64{}
+	}
+
+	ema := make([]float64, len(values)-period+1)
+	ema[0] = calculateAverage(values[:period])
+	multiplier := 2 / float64(period+1)
+	for i := period; i < len(values); i++ {
+		ema[i-period+1] = (values[i]-ema[i-period])*multiplier + ema[i-period]
+	}
+	return ema
+}
+
+// calculateRSI returns Wilder's RSI; the result starts at index period of the input
+func calculateRSI(closes []float64, period int) []float64 {
+	if period <= 0 || len(closes) <= period {
+		return []float64{}
+	}
+
+	gain, loss := 0.0, 0.0
+	for i := 1; i <= period; i++ {
+		change := closes[i] - closes[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+
+	rsiValue := func() float64 {
+		if loss == 0 {
+			// A flat market is neutral, not overbought
+			if gain == 0 {
+				return 50
+			}
+			return 100
+		}
+		return 100 - 100/(1+gain/loss)
+	}
+
+	rsi := []float64{rsiValue()}
+	for i := period + 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		up, down := math.Max(change, 0), math.Max(-change, 0)
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+		rsi = append(rsi, rsiValue())
+	}
+	return rsi
+}
+
+// calculateMACD returns the MACD line, signal line and histogram, all aligned to the last close
+func calculateMACD(closes []float64, fast, slow, signal int) (macd, signalLine, histogram []float64) {
+	fastEMA := calculateEMA(closes, fast)
+	slowEMA := calculateEMA(closes, slow)
+	if len(slowEMA) == 0 || len(fastEMA) < len(slowEMA) {
+		return []float64{}, []float64{}, []float64{}
+	}
+
+	offset := len(fastEMA) - len(slowEMA)
+	macd = make([]float64, len(slowEMA))
+	for i := range slowEMA {
+		macd[i] = fastEMA[i+offset] - slowEMA[i]
+	}
+
+	signalLine = calculateEMA(macd, signal)
+	if len(signalLine) == 0 {
+		return macd, []float64{}, []float64{}
+	}
+	offset = len(macd) - len(signalLine)
+	histogram = make([]float64, len(signalLine))
+	for i := range signalLine {
+		histogram[i] = macd[i+offset] - signalLine[i]
+	}
+	return macd, signalLine, histogram
+}
+
+// calculateBollinger returns the middle, upper and lower bands, aligned like calculateSMA
+func calculateBollinger(closes []float64, period int, stdDevs float64) (middle, upper, lower []float64) {
+	middle = calculateSMA(closes, period)
+	upper = make([]float64, len(middle))
+	lower = make([]float64, len(middle))
+	for i, mean := range middle {
+		variance := 0.0
+		for _, value := range closes[i : i+period] {
+			variance += (value - mean) * (value - mean)
+		}
+		deviation := math.Sqrt(variance / float64(period))
+		upper[i] = mean + stdDevs*deviation
+		lower[i] = mean - stdDevs*deviation
+	}
+	return middle, upper, lower
+}
+
+// calculateATR returns the latest Average True Range using Wilder's smoothing, 0 when there is too little data
+func calculateATR(klines []Kline, period int) float64 {
+	if period <= 0 || len(klines) <= period {
+		return 0
+	}
+
+	trueRanges := make([]float64, len(klines)-1)
+	for i := 1; i < len(klines); i++ {
+		high, low, prevClose := klines[i].High, klines[i].Low, klines[i-1].Close
+		trueRanges[i-1] = math.Max(high-low, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+	}
+
+	atr := calculateAverage(trueRanges[:period])
+	for _, tr := range trueRanges[period:] {
+		atr = (atr*float64(period-1) + tr) / float64(period)
+	}
+	return atr
+}
+
+// calculateOBV returns On-Balance Volume for every close
+func calculateOBV(closes, volumes []float64) []float64 {
+	if len(closes) == 0 || len(closes) != len(volumes) {
+		return []float64{}
+	}
+
+	obv := make([]float64, len(closes))
+	for i := 1; i < len(closes); i++ {
+		switch {
+		case closes[i] > closes[i-1]:
+			obv[i] = obv[i-1] + volumes[i]
+		case closes[i] < closes[i-1]:
+			obv[i] = obv[i-1] - volumes[i]
+		default:
+			obv[i] = obv[i-1]
+		}
+	}
+	return obv
+}
+
+// calculateStochRSI returns the smoothed %K and %D (0-100) of the stochastic oscillator applied to RSI
+func calculateStochRSI(closes []float64, rsiPeriod, stochPeriod, smoothK, smoothD int) (k, d []float64) {
+	rsi := calculateRSI(closes, rsiPeriod)
+	if stochPeriod <= 0 || len(rsi) == 0 || len(rsi) < stochPeriod {
+		return []float64{}, []float64{}
+	}
+
+	raw := make([]float64, len(rsi)-stochPeriod+1)
+	for i := range raw {
+		window := rsi[i : i+stochPeriod]
+		lowest, highest := findMin(window), findMax(window)
+		if highest > lowest {
+			raw[i] = (rsi[i+stochPeriod-1] - lowest) / (highest - lowest) * 100
+		} else {
+			raw[i] = 50
+		}
+	}
+
+	k = calculateSMA(raw, smoothK)
+	d = calculateSMA(k, smoothD)
+	return k, d
+}
+
+// last returns the final element of a series, 0 when it is empty
+func last(series []float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	return series[len(series)-1]
+}
+
+// computeIndicators calculates every indicator's latest value from klines
+func computeIndicators(klines []Kline, config IndicatorConfig) TechnicalIndicators {
+	closes := make([]float64, len(klines))
+	volumes := make([]float64, len(klines))
+	for i, k := range klines {
+		closes[i] = k.Close
+		volumes[i] = k.Volume
+	}
+
+	ind := TechnicalIndicators{
+		Config:   config,
+		SMAShort: last(calculateSMA(closes, config.SMAShort)),
+		SMALong:  last(calculateSMA(closes, config.SMALong)),
+		EMAShort: last(calculateEMA(closes, config.SMAShort)),
+		EMALong:  last(calculateEMA(closes, config.SMALong)),
+		RSI:      last(calculateRSI(closes, config.RSIPeriod)),
+		ATR:      calculateATR(klines, config.RSIPeriod),
+	}
+
+	macd, signal, histogram := calculateMACD(closes, config.MACDFast, config.MACDSlow, config.MACDSignal)
+	ind.MACD, ind.MACDSignal, ind.MACDHistogram = last(macd), last(signal), last(histogram)
+
+	middle, upper, lower := calculateBollinger(closes, config.SMALong, bollingerStdDev)
+	ind.BollingerMiddle, ind.BollingerUpper, ind.BollingerLower = last(middle), last(upper), last(lower)
+	if ind.BollingerUpper > ind.BollingerLower && len(closes) > 0 {
+		ind.BollingerPercentB = (closes[len(closes)-1] - ind.BollingerLower) / (ind.BollingerUpper - ind.BollingerLower)
+	}
+
+	obv := calculateOBV(closes, volumes)
+	ind.OBV = last(obv)
+	if len(obv) > config.SMAShort {
+		ind.OBVRising = ind.OBV > obv[len(obv)-1-config.SMAShort]
+	}
+
+	stochK, stochD := calculateStochRSI(closes, config.RSIPeriod, config.RSIPeriod, stochRSISmoothK, stochRSISmoothD)
+	ind.StochRSIK, ind.StochRSID = last(stochK), last(stochD)
+
+	if len(closes) > 0 && closes[len(closes)-1] > 0 {
+		ind.ATRPercent = ind.ATR / closes[len(closes)-1] * 100
+	}
+
+	// Signals derived from the values above, used by the decision logic
+	ind.Overbought = ind.RSI >= 70 && ind.StochRSIK >= 80
+	ind.Oversold = (ind.RSI > 0 && ind.RSI <= 30) || (len(stochK) > 0 && ind.StochRSIK <= 20)
+	ind.MomentumUp = len(histogram) > 0 && ind.MACDHistogram > 0 && ind.EMAShort > ind.EMALong
+
+	return ind
+}
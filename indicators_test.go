@@ -0,0 +1,161 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// floatsEqual compares two series within a small tolerance
+func floatsEqual(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCalculateSMA(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		period int
+		want   []float64
+	}{
+		{"rolling mean", []float64{1, 2, 3, 4, 5}, 3, []float64{2, 3, 4}},
+		{"period equals length", []float64{2, 4}, 2, []float64{3}},
+		{"too little data", []float64{1, 2}, 3, []float64{}},
+		{"zero period", []float64{1, 2, 3}, 0, []float64{}},
+		{"negative period", []float64{1, 2, 3}, -1, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateSMA(tt.values, tt.period); !floatsEqual(got, tt.want) {
+				t.Errorf("calculateSMA = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateEMA(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		period int
+		want   []float64
+	}{
+		{"seeded with the first SMA", []float64{1, 2, 3, 4, 5}, 3, []float64{2, 3, 4}},
+		{"constant series stays constant", []float64{7, 7, 7, 7}, 2, []float64{7, 7, 7}},
+		{"too little data", []float64{1, 2}, 3, []float64{}},
+		{"zero period", []float64{1, 2, 3}, 0, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateEMA(tt.values, tt.period); !floatsEqual(got, tt.want) {
+				t.Errorf("calculateEMA = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateRSI(t *testing.T) {
+	tests := []struct {
+		name   string
+		closes []float64
+		period int
+		want   []float64
+	}{
+		{"only gains", []float64{1, 2, 3, 4}, 2, []float64{100, 100}},
+		{"only losses", []float64{4, 3, 2, 1}, 2, []float64{0, 0}},
+		{"flat market is neutral", []float64{5, 5, 5, 5}, 2, []float64{50, 50}},
+		{"Wilder smoothing", []float64{1, 2, 1, 2, 1}, 2, []float64{50, 75, 37.5}},
+		{"needs period+1 closes", []float64{1, 2}, 2, []float64{}},
+		{"zero period", []float64{1, 2, 3}, 0, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateRSI(tt.closes, tt.period); !floatsEqual(got, tt.want) {
+				t.Errorf("calculateRSI = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateATR(t *testing.T) {
+	steady := []Kline{
+		{High: 11, Low: 9, Close: 10},
+		{High: 11, Low: 9, Close: 10},
+		{High: 11, Low: 9, Close: 10},
+	}
+	gap := []Kline{
+		{High: 11, Low: 9, Close: 10},
+		{High: 11, Low: 9, Close: 10},
+		{High: 15, Low: 14, Close: 14}, // gaps up: true range runs from the previous close
+	}
+
+	tests := []struct {
+		name   string
+		klines []Kline
+		period int
+		want   float64
+	}{
+		{"constant range", steady, 2, 2},
+		{"gap uses the previous close", gap, 1, 5},
+		{"seed averages the first period", gap, 2, 3.5},
+		{"needs period+1 candles", steady, 3, 0},
+		{"zero period", steady, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateATR(tt.klines, tt.period); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("calculateATR = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateStochRSIBadPeriods(t *testing.T) {
+	closes := make([]float64, 60)
+	for i := range closes {
+		closes[i] = 100 + float64(i%7)
+	}
+
+	tests := []struct {
+		name        string
+		rsiPeriod   int
+		stochPeriod int
+		wantEmpty   bool
+	}{
+		{"zero RSI period", 0, 0, true},
+		{"negative RSI period", -3, -3, true},
+		{"zero stochastic period", 14, 0, true},
+		{"too little history", 14, 50, true},
+		{"valid periods", 14, 14, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, d := calculateStochRSI(closes, tt.rsiPeriod, tt.stochPeriod, stochRSISmoothK, stochRSISmoothD)
+			if (len(k) == 0) != tt.wantEmpty || (len(d) == 0) != tt.wantEmpty {
+				t.Errorf("len(k), len(d) = %d, %d, want empty %v", len(k), len(d), tt.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestLoadIndicatorConfigRejectsBadPeriods(t *testing.T) {
+	t.Setenv("RSI_PERIOD", "0")
+	t.Setenv("SMA_SHORT", "-5")
+	t.Setenv("SMA_LONG", "abc")
+	t.Setenv("MACD_FAST", "8")
+	t.Setenv("MACD_SLOW", "")
+	t.Setenv("MACD_SIGNAL", "")
+
+	config := loadIndicatorConfig()
+	want := IndicatorConfig{RSIPeriod: 14, SMAShort: 10, SMALong: 20, MACDFast: 8, MACDSlow: 26, MACDSignal: 9}
+	if config != want {
+		t.Errorf("config = %+v, want %+v", config, want)
+	}
+}
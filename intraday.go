@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math"
+)
+
+// Finer intervals tried, longest first, when no TIMEFRAME interval has enough history
+var intradayFallbacks = []string{"1h", "15m", "5m"}
+
+const (
+	// The default windows need this many candles; shorter histories get scaled windows
+	fullAnalysisKlines = 60
+	// Below this a timeframe is not analyzed at all
+	minViewKlines = 12
+)
+
+// AnalysisWindows represents the candle counts used by the structure analysis
+type AnalysisWindows struct {
+	MediumMA  int `json:"mediumMa"` // medium-term trend compares MediumMA with LongMA
+	LongMA    int `json:"longMa"`   // long-term trend compares LongMA with TrendMA
+	TrendMA   int `json:"trendMa"`
+	Structure int `json:"structure"` // "daily" high/low window
+	Swing     int `json:"swing"`     // "weekly" high/low window
+	Volume    int `json:"volume"`    // volume baseline
+}
+
+// defaultAnalysisWindows are the original daily-chart windows (14/21/30 MAs, 30 and 7 candle ranges, 21 candle volume)
+var defaultAnalysisWindows = AnalysisWindows{MediumMA: 14, LongMA: 21, TrendMA: 30, Structure: 30, Swing: 7, Volume: 21}
+
+// scalePeriod shrinks a period by factor without going below floor
+func scalePeriod(period int, factor float64, floor int) int {
+	return max(floor, int(math.Round(float64(period)*factor)))
+}
+
+// scaleAnalysisWindows shrinks the windows proportionally when fewer than fullAnalysisKlines candles exist
+func scaleAnalysisWindows(candles int) AnalysisWindows {
+	if candles >= fullAnalysisKlines {
+		return defaultAnalysisWindows
+	}
+
+	factor := float64(candles) / fullAnalysisKlines
+	w := defaultAnalysisWindows
+	scaled := AnalysisWindows{
+		MediumMA:  scalePeriod(w.MediumMA, factor, 3),
+		Structure: scalePeriod(w.Structure, factor, 5),
+		Swing:     scalePeriod(w.Swing, factor, 2),
+		Volume:    scalePeriod(w.Volume, factor, 3),
+	}
+	// Keep the moving averages distinct so the trend comparisons still mean something
+	scaled.LongMA = max(scalePeriod(w.LongMA, factor, 3), scaled.MediumMA+1)
+	scaled.TrendMA = max(scalePeriod(w.TrendMA, factor, 3), scaled.LongMA+1)
+	return scaled
+}
+
+// scaleIndicatorConfig shrinks indicator periods when the history cannot support the configured ones
+func scaleIndicatorConfig(config IndicatorConfig, candles int) IndicatorConfig {
+	needed := max(2*config.SMALong, config.MACDSlow+config.MACDSignal)
+	needed = max(needed, 2*config.RSIPeriod+1)
+	if candles >= needed {
+		return config
+	}
+
+	factor := float64(candles) / float64(needed)
+	scaled := IndicatorConfig{
+		RSIPeriod:  scalePeriod(config.RSIPeriod, factor, 3),
+		SMAShort:   scalePeriod(config.SMAShort, factor, 2),
+		MACDFast:   scalePeriod(config.MACDFast, factor, 2),
+		MACDSignal: scalePeriod(config.MACDSignal, factor, 2),
+	}
+	scaled.SMALong = max(scalePeriod(config.SMALong, factor, 3), scaled.SMAShort+1)
+	scaled.MACDSlow = max(scalePeriod(config.MACDSlow, factor, 3), scaled.MACDFast+1)
+	return scaled
+}
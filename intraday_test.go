@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestScaleAnalysisWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		candles int
+		want    AnalysisWindows
+	}{
+		{"full history keeps the defaults", fullAnalysisKlines, defaultAnalysisWindows},
+		{"long history keeps the defaults", 144, defaultAnalysisWindows},
+		{
+			name:    "half history halves the windows",
+			candles: 30,
+			want:    AnalysisWindows{MediumMA: 7, LongMA: 11, TrendMA: 15, Structure: 15, Swing: 4, Volume: 11},
+		},
+		{
+			name:    "floors hold at the minimum view",
+			candles: minViewKlines,
+			want:    AnalysisWindows{MediumMA: 3, LongMA: 4, TrendMA: 6, Structure: 6, Swing: 2, Volume: 4},
+		},
+		{
+			name:    "moving averages stay distinct",
+			candles: 6,
+			want:    AnalysisWindows{MediumMA: 3, LongMA: 4, TrendMA: 5, Structure: 5, Swing: 2, Volume: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scaleAnalysisWindows(tt.candles)
+			if got != tt.want {
+				t.Errorf("scaleAnalysisWindows(%d) = %+v, want %+v", tt.candles, got, tt.want)
+			}
+			if got.MediumMA >= got.LongMA || got.LongMA >= got.TrendMA {
+				t.Errorf("moving averages not increasing: %+v", got)
+			}
+			if tt.candles < fullAnalysisKlines && (got.TrendMA > tt.candles || got.Structure > tt.candles) {
+				t.Errorf("windows exceed the %d candles available: %+v", tt.candles, got)
+			}
+		})
+	}
+}
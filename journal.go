@@ -0,0 +1,312 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	journalFile = "journal.json"
+	// Finished orders are dropped from the journal after this long
+	journalRetention = 30 * 24 * time.Hour
+)
+
+// JournalEntry represents one order the bot placed, written before it is sent
+type JournalEntry struct {
+	ClientOrderID string    `json:"clientOrderId"`
+	OrderID       string    `json:"orderId,omitempty"`
+	Symbol        string    `json:"symbol"`
+	Side          string    `json:"side"`
+	Type          string    `json:"type"`
+	Quantity      string    `json:"quantity"`
+	Price         string    `json:"price,omitempty"`
+	Status        string    `json:"status"` // PENDING until Binance answers, then Binance's order status or FAILED/NOT_FOUND/REFUSED/UNKNOWN
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+
+	Slippage *SlippageEstimate `json:"slippage,omitempty"` // order book estimate for market orders
+}
+
+// OrderJournal represents every order the bot knows it owns
+type OrderJournal struct {
+	Sequence     int64          `json:"sequence"`
+	Entries      []JournalEntry `json:"entries"`
+	Unknown      []OrderStatus  `json:"unknown"` // open orders not placed by this bot
+	ReconciledAt time.Time      `json:"reconciledAt"`
+}
+
+// journalMu serializes every read-modify-write of the journal file
+var journalMu sync.Mutex
+
+// isOpenJournalStatus reports whether an entry may still be live on the exchange
+func isOpenJournalStatus(status string) bool {
+	switch status {
+	case "PENDING", "UNKNOWN", "NEW", "PARTIALLY_FILLED":
+		return true
+	}
+	return false
+}
+
+// isUnsentJournalStatus reports whether an entry certainly never reached the exchange, so its ID may be sent again
+func isUnsentJournalStatus(status string) bool {
+	switch status {
+	case "FAILED", "NOT_FOUND", "REFUSED":
+		return true
+	}
+	return false
+}
+
+// Strategies that place orders; the name prefixes every client order ID
+const (
+	strategyManual   = "nc"
+	strategyGrid     = "grid"
+	strategyDCA      = "dca"
+	strategyExecutor = "exec"
+	strategyTrailing = "trail"
+	strategyKill     = "kill"
+)
+
+// OrderTag identifies the part of the bot an order belongs to. Key names the decision behind the
+// order (a DCA slot, a grid fill, ...) so a retry, even after a restart, reuses the same client order ID
+type OrderTag struct {
+	Strategy string
+	Key      string
+	Closing  bool    // exits a position (stop, kill switch): must fill in full, so slippage is reported but never limited
+	Stop     float64 // protective stop of the position a BUY adds to; the risk manager sizes it by RISK_PERCENTAGE
+}
+
+// orderStrategy returns the strategy encoded in a client order ID, empty for orders the bot did not place
+func orderStrategy(clientOrderID string) string {
+	if i := strings.Index(clientOrderID, "-"); i > 0 {
+		return clientOrderID[:i]
+	}
+	return ""
+}
+
+// newClientOrderID derives the client order ID from the strategy and the tag's key. Quantity and price
+// are left out because a retry may recompute them. Untagged orders fall back to the journal sequence
+func newClientOrderID(tag OrderTag, sequence int64, symbol, side, orderType, quantity, price string) string {
+	strategy := tag.Strategy
+	if strategy == "" {
+		strategy = strategyManual
+	}
+	if tag.Key != "" {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s", strategy, symbol, side, tag.Key)))
+		return fmt.Sprintf("%s-%s", strategy, hex.EncodeToString(sum[:])[:24])
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%s|%s|%s|%s|%s", sequence, symbol, side, orderType, quantity, price)))
+	return fmt.Sprintf("%s-%d-%s", strategy, sequence, hex.EncodeToString(sum[:])[:12])
+}
+
+// loadJournal reads the order journal
+func loadJournal() (*OrderJournal, error) {
+	journal := &OrderJournal{}
+	if _, err := loadState(journalFile, journal); err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// journalBegin records a PENDING order and returns its entry. sent is true when the same client order ID
+// is already journaled and may have reached Binance; the existing entry is returned unchanged
+func journalBegin(tag OrderTag, symbol, side, orderType, quantity, price string, slippage *SlippageEstimate) (entry JournalEntry, sent bool, err error) {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+
+	journal, err := loadJournal()
+	if err != nil {
+		return JournalEntry{}, false, err
+	}
+
+	journal.Sequence++
+	clientOrderID := newClientOrderID(tag, journal.Sequence, symbol, side, orderType, quantity, price)
+	kept := journal.Entries[:0]
+	for _, existing := range journal.Entries {
+		if existing.ClientOrderID != clientOrderID {
+			kept = append(kept, existing)
+		} else if !isUnsentJournalStatus(existing.Status) {
+			return existing, true, nil
+		}
+	}
+	journal.Entries = kept
+
+	now := time.Now()
+	entry = JournalEntry{
+		ClientOrderID: clientOrderID,
+		Symbol:        symbol,
+		Side:          side,
+		Type:          orderType,
+		Quantity:      quantity,
+		Price:         price,
+		Status:        "PENDING",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		Slippage:      slippage,
+	}
+	journal.Entries = append(journal.Entries, entry)
+
+	return entry, false, saveState(journalFile, journal)
+}
+
+// journalUpdate records Binance's answer for an order
+func journalUpdate(clientOrderID, orderID, status, errMsg string) error {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+
+	journal, err := loadJournal()
+	if err != nil {
+		return err
+	}
+
+	for i := range journal.Entries {
+		entry := &journal.Entries[i]
+		if entry.ClientOrderID != clientOrderID {
+			continue
+		}
+		if orderID != "" {
+			entry.OrderID = orderID
+		}
+		entry.Status = status
+		entry.Error = errMsg
+		entry.UpdatedAt = time.Now()
+		return saveState(journalFile, journal)
+	}
+
+	return fmt.Errorf("ไม่พบ %s ใน order journal", clientOrderID)
+}
+
+// journalRecord updates the journal after Binance answered; the order already exists, so a failed write
+// is reported rather than returned, and reconciliation corrects the entry later
+func journalRecord(clientOrderID, orderID, status, errMsg string) {
+	if err := journalUpdate(clientOrderID, orderID, status, errMsg); err != nil {
+		fmt.Printf("⚠️ ไม่สามารถบันทึก order journal %s (%s): %v\n", clientOrderID, status, err)
+	}
+}
+
+// isOrderNotFound reports whether Binance answered -2013 Order does not exist
+func isOrderNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Body, "-2013")
+}
+
+// ReconcileResult represents what reconciliation found
+type ReconcileResult struct {
+	Open     []JournalEntry
+	Updated  []JournalEntry
+	NotFound []JournalEntry
+	Unknown  []OrderStatus
+}
+
+// reconcileJournal compares the journal with Binance so the bot knows exactly which orders it owns
+func reconcileJournal(client *BinanceClient) (*ReconcileResult, error) {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+
+	journal, err := loadJournal()
+	if err != nil {
+		return nil, err
+	}
+
+	openOrders, err := getOpenOrders(client, "")
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึง open orders: %v", err)
+	}
+
+	result := &ReconcileResult{}
+	known := make(map[string]bool)
+
+	var kept []JournalEntry
+	for _, entry := range journal.Entries {
+		known[entry.ClientOrderID] = true
+
+		if isOpenJournalStatus(entry.Status) {
+			order, err := getOrderByClientID(client, entry.Symbol, entry.ClientOrderID)
+			switch {
+			case err == nil:
+				if order.Status != entry.Status {
+					entry.Status = order.Status
+					entry.UpdatedAt = time.Now()
+					result.Updated = append(result.Updated, entry)
+				}
+				entry.OrderID = strconv.FormatInt(order.OrderID, 10)
+			case isOrderNotFound(err):
+				// Binance never accepted it
+				entry.Status = "NOT_FOUND"
+				entry.UpdatedAt = time.Now()
+				result.NotFound = append(result.NotFound, entry)
+			default:
+				return nil, fmt.Errorf("ไม่สามารถตรวจสอบ order %s: %v", entry.ClientOrderID, err)
+			}
+		}
+
+		if isOpenJournalStatus(entry.Status) {
+			result.Open = append(result.Open, entry)
+		}
+		if isOpenJournalStatus(entry.Status) || time.Since(entry.UpdatedAt) < journalRetention {
+			kept = append(kept, entry)
+		}
+	}
+
+	for _, order := range openOrders {
+		if !known[order.ClientOrderID] {
+			result.Unknown = append(result.Unknown, order)
+		}
+	}
+
+	journal.Entries = kept
+	journal.Unknown = result.Unknown
+	journal.ReconciledAt = time.Now()
+	if err := saveState(journalFile, journal); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// printReconcileResult prints the orders the bot owns and flags the ones it does not recognize
+func printReconcileResult(result *ReconcileResult) {
+	fmt.Printf("📓 Order journal: เป็นเจ้าของ %d orders ที่เปิดอยู่\n", len(result.Open))
+	for _, entry := range result.Updated {
+		fmt.Printf("   🔄 %s %s %s → %s\n", entry.Symbol, entry.Side, entry.ClientOrderID, entry.Status)
+	}
+	for _, entry := range result.NotFound {
+		fmt.Printf("   ❔ %s %s %s ไม่ถึง Binance (ไม่มีการสร้าง order)\n", entry.Symbol, entry.Side, entry.ClientOrderID)
+	}
+
+	if len(result.Unknown) > 0 {
+		sort.Slice(result.Unknown, func(i, j int) bool {
+			return result.Unknown[i].Symbol < result.Unknown[j].Symbol
+		})
+		fmt.Printf("⚠️ พบ %d open orders ที่บอทไม่รู้จัก:\n", len(result.Unknown))
+		for _, order := range result.Unknown {
+			fmt.Printf("   🚩 %s #%d %s %s ราคา %s จำนวน %s (clientOrderId %s)\n",
+				order.Symbol, order.OrderID, order.Side, order.Type, order.Price, order.OrigQty, order.ClientOrderID)
+		}
+	}
+}
+
+// reconcileOnStartup runs reconciliation before a trading command starts
+func reconcileOnStartup(client *BinanceClient) error {
+	result, err := reconcileJournal(client)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถตรวจสอบ order journal: %v", err)
+	}
+	printReconcileResult(result)
+	return nil
+}
+
+// runJournalCommand handles: journal
+func runJournalCommand(args []string) error {
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+	return reconcileOnStartup(client)
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const killSwitchFile = "killswitch.json"
+
+// KillSwitchConfig represents the drawdown limits loaded from .env
+type KillSwitchConfig struct {
+	MaxDailyDrawdown float64 // MAX_DAILY_DRAWDOWN: percent below today's equity high
+	MaxTotalDrawdown float64 // MAX_TOTAL_DRAWDOWN: percent below the all-time equity high
+	Flatten          bool    // KILL_SWITCH_FLATTEN: market-sell every coin when tripped
+}
+
+// KillSwitchTrip represents why and how the kill switch fired
+type KillSwitchTrip struct {
+	TrippedAt       time.Time `json:"trippedAt"`
+	Rule            string    `json:"rule"` // DAILY_DRAWDOWN or TOTAL_DRAWDOWN
+	Reason          string    `json:"reason"`
+	Equity          float64   `json:"equity"`
+	HighWaterMark   float64   `json:"highWaterMark"`
+	DrawdownPercent float64   `json:"drawdownPercent"`
+	LimitPercent    float64   `json:"limitPercent"`
+	CancelledOrders int       `json:"cancelledOrders"`
+	Flattened       []string  `json:"flattened,omitempty"`
+	Errors          []string  `json:"errors,omitempty"`
+	ResetAt         time.Time `json:"resetAt,omitempty"`
+}
+
+// KillSwitchState represents the equity high-water marks and the trip record
+type KillSwitchState struct {
+	HighWaterMark    float64          `json:"highWaterMark"`
+	Day              string           `json:"day"` // UTC date the daily high belongs to
+	DayHighWaterMark float64          `json:"dayHighWaterMark"`
+	LastEquity       float64          `json:"lastEquity"`
+	CheckedAt        time.Time        `json:"checkedAt"`
+	Tripped          bool             `json:"tripped"`
+	History          []KillSwitchTrip `json:"history"` // most recent last; the last one is active while Tripped
+}
+
+// loadKillSwitchConfig reads the drawdown limits; 0 disables a limit
+func loadKillSwitchConfig() KillSwitchConfig {
+	return KillSwitchConfig{
+		MaxDailyDrawdown: envFloat("MAX_DAILY_DRAWDOWN", 10),
+		MaxTotalDrawdown: envFloat("MAX_TOTAL_DRAWDOWN", 25),
+		Flatten:          envBool("KILL_SWITCH_FLATTEN", false),
+	}
+}
+
+// loadKillSwitch reads the kill switch state
+func loadKillSwitch() (*KillSwitchState, error) {
+	state := &KillSwitchState{}
+	if _, err := loadState(killSwitchFile, state); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลดสถานะ kill switch: %v", err)
+	}
+	return state, nil
+}
+
+// save persists the kill switch state
+func (k *KillSwitchState) save() error {
+	return saveState(killSwitchFile, k)
+}
+
+// activeTrip returns the trip that is currently halting trading
+func (k *KillSwitchState) activeTrip() *KillSwitchTrip {
+	if !k.Tripped || len(k.History) == 0 {
+		return nil
+	}
+	return &k.History[len(k.History)-1]
+}
+
+// killSwitchBlock returns an error while the kill switch is tripped; a state that cannot be read also blocks
+func killSwitchBlock() error {
+	state, err := loadKillSwitch()
+	if err != nil {
+		return err
+	}
+	if trip := state.activeTrip(); trip != nil {
+		return &RiskError{Rule: "KILL_SWITCH", Reason: fmt.Sprintf(
+			"หยุดเทรดตั้งแต่ %s: %s (ใช้ `guard reset` เพื่อเริ่มใหม่)", trip.TrippedAt.Format("2006-01-02 15:04"), trip.Reason)}
+	}
+	return nil
+}
+
+// evaluateDrawdown moves the high-water marks with equity and returns a trip when a limit is breached
+func evaluateDrawdown(state *KillSwitchState, config KillSwitchConfig, equity float64, now time.Time) *KillSwitchTrip {
+	day := now.UTC().Format("2006-01-02")
+	if state.Day != day {
+		state.Day = day
+		state.DayHighWaterMark = equity
+	}
+	if equity > state.DayHighWaterMark {
+		state.DayHighWaterMark = equity
+	}
+	if equity > state.HighWaterMark {
+		state.HighWaterMark = equity
+	}
+	state.LastEquity = equity
+	state.CheckedAt = now
+
+	limits := []struct {
+		rule, label string
+		high, limit float64
+	}{
+		{"TOTAL_DRAWDOWN", "ทั้งหมด", state.HighWaterMark, config.MaxTotalDrawdown},
+		{"DAILY_DRAWDOWN", "วันนี้", state.DayHighWaterMark, config.MaxDailyDrawdown},
+	}
+	for _, l := range limits {
+		if l.limit <= 0 || l.high <= 0 {
+			continue
+		}
+		drawdown := (l.high - equity) / l.high * 100
+		if drawdown >= l.limit {
+			return &KillSwitchTrip{
+				TrippedAt:       now,
+				Rule:            l.rule,
+				Reason:          fmt.Sprintf("equity $%.2f ลดลง %.1f%% จากจุดสูงสุด%s $%.2f (เกินเพดาน %.1f%%)", equity, drawdown, l.label, l.high, l.limit),
+				Equity:          equity,
+				HighWaterMark:   l.high,
+				DrawdownPercent: drawdown,
+				LimitPercent:    l.limit,
+			}
+		}
+	}
+	return nil
+}
+
+// checkEquityGuard values the account and fires the kill switch when a drawdown limit is breached
+func checkEquityGuard(client *BinanceClient) error {
+	state, err := loadKillSwitch()
+	if err != nil {
+		return err
+	}
+	if state.Tripped {
+		return nil
+	}
+
+	portfolio, err := getPortfolio(client, 0)
+	if err != nil {
+		return err
+	}
+	if portfolio.TotalEquity <= 0 {
+		return nil
+	}
+
+	trip := evaluateDrawdown(state, loadKillSwitchConfig(), portfolio.TotalEquity, time.Now())
+	if trip == nil {
+		return state.save()
+	}
+	return tripKillSwitch(client, state, *trip)
+}
+
+// tripKillSwitch halts trading, cancels every open order and optionally flattens all coins
+func tripKillSwitch(client *BinanceClient, state *KillSwitchState, trip KillSwitchTrip) error {
+	// Persist first so every order from here on is refused, even if cancelling fails
+	state.Tripped = true
+	state.History = append(state.History, trip)
+	if err := state.save(); err != nil {
+		return err
+	}
+	record := &state.History[len(state.History)-1]
+
+	fmt.Printf("\n🚨🚨 KILL SWITCH [%s]: %s\n", trip.Rule, trip.Reason)
+
+	openOrders, err := getOpenOrders(client, "")
+	if err != nil {
+		record.Errors = append(record.Errors, fmt.Sprintf("ไม่สามารถดึง open orders: %v", err))
+	}
+	symbols := make(map[string]bool)
+	for _, order := range openOrders {
+		symbols[order.Symbol] = true
+	}
+	for symbol := range symbols {
+		cancelled, err := cancelAllOrders(client, symbol)
+		if err != nil {
+			record.Errors = append(record.Errors, fmt.Sprintf("ยกเลิก orders %s ไม่สำเร็จ: %v", symbol, err))
+			continue
+		}
+		record.CancelledOrders += len(cancelled)
+	}
+	fmt.Printf("🛑 ยกเลิก %d orders ใน %d เหรียญ\n", record.CancelledOrders, len(symbols))
+
+	if loadKillSwitchConfig().Flatten {
+		flattenPositions(client, record)
+	}
+
+	for _, msg := range record.Errors {
+		fmt.Printf("⚠️ %s\n", msg)
+	}
+	fmt.Println("⛔ ปฏิเสธทุก order ใหม่ (ยกเว้น market sell) จนกว่าจะสั่ง `guard reset`")
+	return state.save()
+}
+
+// flattenPositions market-sells the free balance of every coin that trades against USDT
+func flattenPositions(client *BinanceClient, record *KillSwitchTrip) {
+	balances, err := getAccountBalances(client)
+	if err != nil {
+		record.Errors = append(record.Errors, fmt.Sprintf("ไม่สามารถดึงยอดเงิน: %v", err))
+		return
+	}
+	prices, err := getAllPrices()
+	if err != nil {
+		record.Errors = append(record.Errors, fmt.Sprintf("ไม่สามารถดึงราคา: %v", err))
+		return
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Asset < balances[j].Asset
+	})
+	for _, balance := range balances {
+		symbol := balance.Asset + "USDT"
+		price, ok := prices[symbol]
+		if balance.Asset == "USDT" || !ok || balance.Free <= 0 {
+			continue
+		}
+
+		rules, err := getSymbolRules(symbol)
+		if err != nil {
+			record.Errors = append(record.Errors, fmt.Sprintf("%s: %v", symbol, err))
+			continue
+		}
+		quantity := floorToStep(balance.Free, rules.StepSize)
+		if quantity < rules.MinQty || quantity*price < rules.MinNotional {
+			continue
+		}
+
+		formatted := rules.formatQuantity(quantity)
+		tag := OrderTag{Strategy: strategyKill, Key: strconv.FormatInt(record.TrippedAt.UnixNano(), 10), Closing: true}
+		if _, err := placeOrder(client, tag, symbol, "SELL", "MARKET", formatted, ""); err != nil {
+			record.Errors = append(record.Errors, fmt.Sprintf("ขาย %s ไม่สำเร็จ: %v", symbol, err))
+			continue
+		}
+		record.Flattened = append(record.Flattened, fmt.Sprintf("%s %s", symbol, formatted))
+		fmt.Printf("💥 ขาย %s %s\n", formatted, symbol)
+	}
+}
+
+// resetKillSwitch re-enables trading and restarts the high-water marks from current equity
+func resetKillSwitch(client *BinanceClient) error {
+	state, err := loadKillSwitch()
+	if err != nil {
+		return err
+	}
+	trip := state.activeTrip()
+	if trip == nil {
+		fmt.Println("✅ Kill switch ไม่ได้ทำงานอยู่")
+		return nil
+	}
+
+	portfolio, err := getPortfolio(client, 0)
+	if err != nil {
+		return err
+	}
+
+	trip.ResetAt = time.Now()
+	state.Tripped = false
+	state.HighWaterMark = portfolio.TotalEquity
+	state.Day = time.Now().UTC().Format("2006-01-02")
+	state.DayHighWaterMark = portfolio.TotalEquity
+	state.LastEquity = portfolio.TotalEquity
+	state.CheckedAt = time.Now()
+
+	fmt.Printf("🔓 รีเซ็ต kill switch แล้ว - จุดสูงสุดใหม่ $%.2f\n", portfolio.TotalEquity)
+	return state.save()
+}
+
+// printKillSwitchStatus shows equity against both high-water marks and the trip history
+func printKillSwitchStatus(state *KillSwitchState, config KillSwitchConfig) {
+	fmt.Println("🧯 Equity Guard:")
+	if trip := state.activeTrip(); trip != nil {
+		fmt.Printf("   🚨 หยุดเทรดอยู่ตั้งแต่ %s [%s]\n", trip.TrippedAt.Format("2006-01-02 15:04"), trip.Rule)
+	} else {
+		fmt.Println("   ✅ เปิดให้เทรด")
+	}
+
+	drawdown := func(high float64) float64 {
+		if high <= 0 {
+			return 0
+		}
+		return (high - state.LastEquity) / high * 100
+	}
+	fmt.Printf("   • Equity ล่าสุด: $%.2f (%s)\n", state.LastEquity, state.CheckedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("   • จุดสูงสุดวันนี้: $%.2f, drawdown %.1f%% (เพดาน %.1f%%, MAX_DAILY_DRAWDOWN)\n",
+		state.DayHighWaterMark, drawdown(state.DayHighWaterMark), config.MaxDailyDrawdown)
+	fmt.Printf("   • จุดสูงสุดทั้งหมด: $%.2f, drawdown %.1f%% (เพดาน %.1f%%, MAX_TOTAL_DRAWDOWN)\n",
+		state.HighWaterMark, drawdown(state.HighWaterMark), config.MaxTotalDrawdown)
+	fmt.Printf("   • ขายทุกเหรียญเมื่อทำงาน: %v (KILL_SWITCH_FLATTEN)\n", config.Flatten)
+
+	if len(state.History) > 0 {
+		fmt.Println("📜 ประวัติ:")
+		for _, trip := range state.History {
+			fmt.Printf("   %s [%s] %s\n", trip.TrippedAt.Format("2006-01-02 15:04"), trip.Rule, trip.Reason)
+			fmt.Printf("      ยกเลิก %d orders", trip.CancelledOrders)
+			if len(trip.Flattened) > 0 {
+				fmt.Printf(", ขาย %d เหรียญ", len(trip.Flattened))
+			}
+			if len(trip.Errors) > 0 {
+				fmt.Printf(", ข้อผิดพลาด %d รายการ", len(trip.Errors))
+			}
+			if !trip.ResetAt.IsZero() {
+				fmt.Printf(", รีเซ็ต %s", trip.ResetAt.Format("2006-01-02 15:04"))
+			}
+			fmt.Println()
+		}
+	}
+}
+
+// runGuardLoop checks equity every GUARD_INTERVAL seconds until SIGINT/SIGTERM
+func runGuardLoop(client *BinanceClient) error {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	interval := time.Duration(envInt("GUARD_INTERVAL", 30)) * time.Second
+	fmt.Printf("🧯 ตรวจ equity ทุก %v (Ctrl+C เพื่อหยุด)\n", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := checkEquityGuard(client); err != nil {
+			fmt.Printf("⚠️ Equity guard: %v\n", err)
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runGuardCommand handles: guard [status] | guard run | guard reset
+func runGuardCommand(args []string) error {
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	command := "status"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "status":
+		if err := checkEquityGuard(client); err != nil {
+			return err
+		}
+		state, err := loadKillSwitch()
+		if err != nil {
+			return err
+		}
+		printKillSwitchStatus(state, loadKillSwitchConfig())
+		return nil
+	case "run":
+		return runGuardLoop(client)
+	case "reset":
+		return resetKillSwitch(client)
+	}
+	return errors.New("ใช้งาน: guard [status] | guard run | guard reset")
+}
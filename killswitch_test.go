@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateDrawdown(t *testing.T) {
+	config := KillSwitchConfig{MaxDailyDrawdown: 10, MaxTotalDrawdown: 20}
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	type check struct {
+		equity float64
+		at     time.Time
+	}
+	tests := []struct {
+		name     string
+		config   KillSwitchConfig
+		checks   []check
+		wantRule string // rule of the last check's trip, empty for none
+		wantHigh float64
+		wantDay  float64
+	}{
+		{
+			name:     "new highs raise both marks",
+			config:   config,
+			checks:   []check{{1000, day1}, {1100, day1}},
+			wantHigh: 1100,
+			wantDay:  1100,
+		},
+		{
+			name:     "daily drawdown at the limit trips",
+			config:   config,
+			checks:   []check{{1000, day1}, {900, day1}},
+			wantRule: "DAILY_DRAWDOWN",
+			wantHigh: 1000,
+			wantDay:  1000,
+		},
+		{
+			name:     "just under the daily limit holds",
+			config:   config,
+			checks:   []check{{1000, day1}, {901, day1}},
+			wantHigh: 1000,
+			wantDay:  1000,
+		},
+		{
+			name:     "a new UTC day resets the daily mark",
+			config:   config,
+			checks:   []check{{1000, day1}, {910, day1}, {850, day2}},
+			wantHigh: 1000,
+			wantDay:  850,
+		},
+		{
+			name:     "total drawdown builds up across days",
+			config:   config,
+			checks:   []check{{1000, day1}, {910, day1}, {850, day2}, {800, day2}},
+			wantRule: "TOTAL_DRAWDOWN",
+			wantHigh: 1000,
+			wantDay:  850,
+		},
+		{
+			name:     "zero limits never trip",
+			config:   KillSwitchConfig{},
+			checks:   []check{{1000, day1}, {100, day1}},
+			wantHigh: 1000,
+			wantDay:  1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &KillSwitchState{}
+			var trip *KillSwitchTrip
+			for _, c := range tt.checks {
+				trip = evaluateDrawdown(state, tt.config, c.equity, c.at)
+			}
+
+			rule := ""
+			if trip != nil {
+				rule = trip.Rule
+			}
+			if rule != tt.wantRule {
+				t.Errorf("trip rule = %q, want %q", rule, tt.wantRule)
+			}
+			if state.HighWaterMark != tt.wantHigh {
+				t.Errorf("HighWaterMark = %v, want %v", state.HighWaterMark, tt.wantHigh)
+			}
+			if state.DayHighWaterMark != tt.wantDay {
+				t.Errorf("DayHighWaterMark = %v, want %v", state.DayHighWaterMark, tt.wantDay)
+			}
+		})
+	}
+}
@@ -10,9 +10,18 @@ import (
 
 // Get klines data for analysis
 func getKlines(client *BinanceClient, symbol, interval string, limit int) ([]Kline, error) {
-	url := fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%s&interval=%s&limit=%d",
-		symbol, interval, limit)
+	return fetchKlines(fmt.Sprintf("%s/api/v3/klines?symbol=%s&interval=%s&limit=%d",
+		binanceBaseURL(), symbol, interval, limit))
+}
+
+// getKlinesFrom returns klines starting at startTime (ms); startTime 0 gives the first candles ever traded
+func getKlinesFrom(client *BinanceClient, symbol, interval string, startTime int64, limit int) ([]Kline, error) {
+	return fetchKlines(fmt.Sprintf("%s/api/v3/klines?symbol=%s&interval=%s&startTime=%d&limit=%d",
+		binanceBaseURL(), symbol, interval, startTime, limit))
+}
 
+// fetchKlines downloads and parses a klines URL
+func fetchKlines(url string) ([]Kline, error) {
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Buffers in volatility units around structure levels
+const (
+	stopStructureBuffer   = 0.5 // stop sits this far below the support it protects
+	targetStructureBuffer = 0.1 // targets sit this far below the resistance they aim at
+	targetStructureReach  = 1.0 // resistance this close to an ATR target replaces it
+)
+
+// LevelConfig represents the stop and target settings loaded from .env
+type LevelConfig struct {
+	StopMinATR    float64   // STOP_ATR_MIN: closest stop to the entry, in ATR
+	StopMaxATR    float64   // STOP_ATR_MAX: furthest stop from the entry, in ATR
+	TargetATR     []float64 // TARGET_ATR_MULTIPLES: targets above the entry, in ATR
+	MinRewardRisk float64   // MIN_REWARD_RISK: reward-to-risk (first resistance or target) needed to accumulate
+}
+
+// PriceLevel represents a stop or target with its distance from the entry
+type PriceLevel struct {
+	Price       float64 `json:"price"`
+	Basis       string  `json:"basis"`                // atr or structure
+	ATRDistance float64 `json:"atrDistance"`          // distance from the entry in volatility units
+	RewardRisk  float64 `json:"rewardRisk,omitempty"` // targets only: reward per unit of risk to the stop
+}
+
+// TradeLevels represents the volatility-scaled stop and targets of a setup
+type TradeLevels struct {
+	Volatility       float64      `json:"volatility"`       // price move of one volatility unit
+	VolatilitySource string       `json:"volatilitySource"` // atr, or realized when there is too little data for ATR
+	Stop             PriceLevel   `json:"stop"`
+	Targets          []PriceLevel `json:"targets"`
+	FirstResistance  float64      `json:"firstResistance,omitempty"` // nearest resistance at least StopMinATR above the entry
+	RewardRisk       float64      `json:"rewardRisk"`                // to the first resistance, or the first target without one
+	MinRewardRisk    float64      `json:"minRewardRisk"`
+	Rejected         bool         `json:"rejected"`
+	RejectReason     string       `json:"rejectReason,omitempty"`
+}
+
+// loadLevelConfig reads the stop band, target multiples and minimum reward-to-risk from .env
+func loadLevelConfig() LevelConfig {
+	config := LevelConfig{
+		StopMinATR:    envFloat("STOP_ATR_MIN", 1),
+		StopMaxATR:    envFloat("STOP_ATR_MAX", 2.5),
+		TargetATR:     []float64{4, 6, 9},
+		MinRewardRisk: envFloat("MIN_REWARD_RISK", 1.5),
+	}
+
+	if raw := os.Getenv("TARGET_ATR_MULTIPLES"); raw != "" {
+		multiples, err := parseTargetMultiples(raw)
+		if err != nil {
+			fmt.Printf("⚠️ TARGET_ATR_MULTIPLES: %v - ใช้ค่าเริ่มต้น\n", err)
+		} else {
+			config.TargetATR = multiples
+		}
+	}
+	if config.StopMaxATR < config.StopMinATR {
+		config.StopMaxATR = config.StopMinATR
+	}
+	// A setup with no structure has its stop at StopMaxATR and is paid by the first target
+	if config.TargetATR[0]/config.StopMaxATR < config.MinRewardRisk {
+		fmt.Printf("⚠️ TARGET_ATR_MULTIPLES เป้าแรก %.1f ATR / STOP_ATR_MAX %.1f ต่ำกว่า MIN_REWARD_RISK %.1f - setup ที่ไม่มีแนวต้านจะถูกปฏิเสธเสมอ\n",
+			config.TargetATR[0], config.StopMaxATR, config.MinRewardRisk)
+	}
+	return config
+}
+
+// parseTargetMultiples parses a comma-separated list of positive ATR multiples, sorted ascending
+func parseTargetMultiples(raw string) ([]float64, error) {
+	var multiples []float64
+	for _, part := range strings.Split(raw, ",") {
+		multiple, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || multiple <= 0 {
+			return nil, fmt.Errorf("%q ไม่ถูกต้อง", part)
+		}
+		multiples = append(multiples, multiple)
+	}
+	sort.Float64s(multiples)
+	return multiples, nil
+}
+
+// realizedVolatility returns the standard deviation of close-to-close returns, as a fraction
+func realizedVolatility(klines []Kline) float64 {
+	var returns []float64
+	for i := 1; i < len(klines); i++ {
+		if klines[i-1].Close > 0 {
+			returns = append(returns, klines[i].Close/klines[i-1].Close-1)
+		}
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	mean := calculateAverage(returns)
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)-1))
+}
+
+// computeTradeLevels places the stop below the nearest support and targets at ATR multiples,
+// snapped under nearby resistance. Reward-to-risk is measured to the first resistance at least
+// StopMinATR above the entry; setups without volatility, with a support further than StopMaxATR or paying
+// less than MinRewardRisk are rejected
+func computeTradeLevels(entry, atr float64, klines []Kline, supports, resistances []float64, config LevelConfig) TradeLevels {
+	levels := TradeLevels{Volatility: atr, VolatilitySource: "atr", MinRewardRisk: config.MinRewardRisk}
+	if levels.Volatility <= 0 {
+		levels.Volatility = realizedVolatility(klines) * entry
+		levels.VolatilitySource = "realized"
+	}
+	unit := levels.Volatility
+	if entry <= 0 || unit <= 0 {
+		return levels.reject("ไม่มีข้อมูลความผันผวนสำหรับตั้ง stop")
+	}
+
+	// Stop: below the nearest support, at least StopMinATR away; StopMaxATR without a support
+	levels.Stop = PriceLevel{Price: entry - config.StopMaxATR*unit, Basis: "atr"}
+	nearest := 0.0
+	for _, support := range supports {
+		if support < entry && support > nearest {
+			nearest = support
+		}
+	}
+	if nearest > 0 {
+		levels.Stop = PriceLevel{Price: nearest - stopStructureBuffer*unit, Basis: "structure"}
+	}
+	if levels.Stop.Price > entry-config.StopMinATR*unit {
+		levels.Stop = PriceLevel{Price: entry - config.StopMinATR*unit, Basis: "atr"}
+	}
+	// Very volatile coins: never place a stop at or below zero
+	levels.Stop.Price = math.Max(levels.Stop.Price, entry*0.01)
+	risk := entry - levels.Stop.Price
+	levels.Stop.ATRDistance = risk / unit
+
+	// Targets: ATR multiples, moved just under a resistance that is close by
+	sorted := append([]float64(nil), resistances...)
+	sort.Float64s(sorted)
+	previous := entry
+	for _, multiple := range config.TargetATR {
+		target := PriceLevel{Price: entry + multiple*unit, Basis: "atr"}
+		for _, resistance := range sorted {
+			snapped := resistance - targetStructureBuffer*unit
+			if snapped > previous && math.Abs(resistance-target.Price) <= targetStructureReach*unit {
+				target = PriceLevel{Price: snapped, Basis: "structure"}
+				break
+			}
+		}
+		if target.Price <= previous {
+			continue
+		}
+
+		target.ATRDistance = (target.Price - entry) / unit
+		target.RewardRisk = (target.Price - entry) / risk
+		levels.Targets = append(levels.Targets, target)
+		previous = target.Price
+	}
+
+	// Reward: up to the first resistance above the entry, which caps the trade; the first target otherwise.
+	// Levels closer than StopMinATR, such as the wick of the current candle, are noise rather than a cap
+	for _, resistance := range sorted {
+		if resistance > entry+config.StopMinATR*unit {
+			levels.FirstResistance = resistance
+			break
+		}
+	}
+	if levels.FirstResistance > 0 {
+		levels.RewardRisk = (levels.FirstResistance - entry) / risk
+	} else if len(levels.Targets) > 0 {
+		levels.RewardRisk = levels.Targets[0].RewardRisk
+	}
+
+	if levels.Stop.ATRDistance > config.StopMaxATR+1e-9 {
+		return levels.reject(fmt.Sprintf("แนวรับห่าง %.1f ATR เกิน STOP_ATR_MAX %.1f", levels.Stop.ATRDistance, config.StopMaxATR))
+	}
+	if levels.RewardRisk < config.MinRewardRisk {
+		return levels.reject(fmt.Sprintf("reward/risk %.1f ต่ำกว่าขั้นต่ำ %.1f", levels.RewardRisk, config.MinRewardRisk))
+	}
+	return levels
+}
+
+// reject marks the setup as not worth taking
+func (l TradeLevels) reject(reason string) TradeLevels {
+	l.Rejected = true
+	l.RejectReason = reason
+	return l
+}
+
+// targetPrices returns the target prices in order
+func (l TradeLevels) targetPrices() []float64 {
+	prices := make([]float64, len(l.Targets))
+	for i, target := range l.Targets {
+		prices[i] = target.Price
+	}
+	return prices
+}
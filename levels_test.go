@@ -0,0 +1,118 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeTradeLevels(t *testing.T) {
+	config := LevelConfig{StopMinATR: 1, StopMaxATR: 2.5, TargetATR: []float64{4, 6, 9}, MinRewardRisk: 1.5}
+
+	tests := []struct {
+		name         string
+		atr          float64
+		supports     []float64
+		resistances  []float64
+		wantStop     PriceLevel
+		wantTargets  []float64
+		wantReward   float64
+		wantRejected bool
+	}{
+		{
+			name:        "no structure passes on the widest ATR stop",
+			atr:         2,
+			wantStop:    PriceLevel{Price: 95, Basis: "atr", ATRDistance: 2.5},
+			wantTargets: []float64{108, 112, 118},
+			wantReward:  1.6,
+		},
+		{
+			name:        "stop under support, reward to the first resistance",
+			atr:         2,
+			supports:    []float64{90, 97},
+			resistances: []float64{110},
+			wantStop:    PriceLevel{Price: 96, Basis: "structure", ATRDistance: 2},
+			wantTargets: []float64{109.8, 112, 118},
+			wantReward:  2.5,
+		},
+		{
+			name:         "resistance just above the entry caps the trade",
+			atr:          2,
+			supports:     []float64{97},
+			resistances:  []float64{103, 120},
+			wantStop:     PriceLevel{Price: 96, Basis: "structure", ATRDistance: 2},
+			wantTargets:  []float64{108, 112, 119.8},
+			wantReward:   0.75,
+			wantRejected: true,
+		},
+		{
+			name:        "a wick within the minimum stop distance is not a cap",
+			atr:         2,
+			supports:    []float64{97},
+			resistances: []float64{101.5, 112},
+			wantStop:    PriceLevel{Price: 96, Basis: "structure", ATRDistance: 2},
+			wantTargets: []float64{108, 111.8, 118},
+			wantReward:  3,
+		},
+		{
+			name:         "support beyond the widest stop is rejected, not clamped",
+			atr:          2,
+			supports:     []float64{90},
+			resistances:  []float64{130},
+			wantStop:     PriceLevel{Price: 89, Basis: "structure", ATRDistance: 5.5},
+			wantTargets:  []float64{108, 112, 118},
+			wantReward:   30.0 / 11,
+			wantRejected: true,
+		},
+		{
+			name:        "support too close widens to the minimum stop",
+			atr:         2,
+			supports:    []float64{99.5},
+			resistances: []float64{104},
+			wantStop:    PriceLevel{Price: 98, Basis: "atr", ATRDistance: 1},
+			wantTargets: []float64{108, 112, 118},
+			wantReward:  2,
+		},
+		{
+			name:         "no volatility is rejected",
+			supports:     []float64{97},
+			resistances:  []float64{110},
+			wantRejected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeTradeLevels(100, tt.atr, nil, tt.supports, tt.resistances, config)
+			if got.Rejected != tt.wantRejected {
+				t.Errorf("Rejected = %v (%s), want %v", got.Rejected, got.RejectReason, tt.wantRejected)
+			}
+			if got.Rejected && got.RejectReason == "" {
+				t.Error("rejected without a reason")
+			}
+			if got.Stop.Basis != tt.wantStop.Basis {
+				t.Errorf("stop basis = %q, want %q", got.Stop.Basis, tt.wantStop.Basis)
+			}
+			if math.Abs(got.Stop.Price-tt.wantStop.Price) > 1e-9 || math.Abs(got.Stop.ATRDistance-tt.wantStop.ATRDistance) > 1e-9 {
+				t.Errorf("stop = %v (%v ATR), want %v (%v ATR)", got.Stop.Price, got.Stop.ATRDistance, tt.wantStop.Price, tt.wantStop.ATRDistance)
+			}
+			if !floatsEqual(got.targetPrices(), tt.wantTargets) {
+				t.Errorf("targets = %v, want %v", got.targetPrices(), tt.wantTargets)
+			}
+			if math.Abs(got.RewardRisk-tt.wantReward) > 1e-9 {
+				t.Errorf("RewardRisk = %v, want %v", got.RewardRisk, tt.wantReward)
+			}
+		})
+	}
+}
+
+func TestLoadLevelConfigDefaults(t *testing.T) {
+	for _, key := range []string{"STOP_ATR_MIN", "STOP_ATR_MAX", "TARGET_ATR_MULTIPLES", "MIN_REWARD_RISK"} {
+		t.Setenv(key, "")
+	}
+	config := loadLevelConfig()
+
+	// With the defaults a setup with no structure at all must be able to pass
+	if levels := computeTradeLevels(100, 2, nil, nil, nil, config); levels.Rejected {
+		t.Errorf("ATR-only setup rejected with the defaults: %s", levels.RejectReason)
+	}
+}
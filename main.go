@@ -4,11 +4,62 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 )
 
 func main() {
+	loadEnvFile(".env")
+	if useTestnet() {
+		fmt.Fprintf(os.Stderr, "🧪 TESTNET: ส่งคำขอและคำสั่งซื้อขายไปที่ %s\n", binanceTestnetURL)
+	}
+
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "grid":
+			err = runGridCommand(os.Args[2:])
+		case "daemon":
+			err = runDaemonCommand(os.Args[2:])
+		case "cancel":
+			err = runCancelCommand(os.Args[2:])
+		case "portfolio":
+			err = runPortfolioCommand(os.Args[2:])
+		case "pnl":
+			err = runPnLCommand(os.Args[2:])
+		case "risk":
+			err = runRiskCommand(os.Args[2:])
+		case "journal":
+			err = runJournalCommand(os.Args[2:])
+		case "stream":
+			err = runStreamCommand(os.Args[2:])
+		case "dca":
+			err = runDCACommand(os.Args[2:])
+		case "trail":
+			err = runTrailCommand(os.Args[2:])
+		case "exec":
+			err = runExecCommand(os.Args[2:])
+		case "guard":
+			err = runGuardCommand(os.Args[2:])
+		default:
+			err = fmt.Errorf("ไม่รู้จักคำสั่ง %q (คำสั่งที่มี: grid, daemon, cancel, portfolio, pnl, risk, journal, stream, dca, trail, exec, guard)", os.Args[1])
+		}
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		return
+	}
+
+	runScan()
+}
+
+// runScan runs one new-coin scan followed by the AI accumulation analysis
+func runScan() {
+	loadEnvFile(".env")
+	criteria := newCoinCriteria()
+
 	fmt.Println("🚀 ตัวสแกนเหรียญใหม่ Binance")
-	fmt.Println("🆕 เฉพาะเหรียญใหม่ (≤30 วัน)")
+	fmt.Printf("🆕 เฉพาะเหรียญใหม่ (%d-%d วัน)\n", criteria.MinAge, criteria.MaxAge)
 	fmt.Println("🎯 โอกาสเข้าก่อนใคร + AI วิเคราะห์การสะสม")
 	fmt.Println("===============================================")
 
@@ -44,6 +95,12 @@ func main() {
 			coin.Reason)
 	}
 
+	// Where each score came from, so equal scores can be told apart
+	fmt.Printf("\n🧮 ที่มาของคะแนน (%s):\n", bestCoins[0].ScoreModel)
+	for _, coin := range bestCoins {
+		fmt.Printf("   • %s %.1f = %s\n", coin.Symbol, coin.Score, formatScoreBreakdown(coin.Breakdown))
+	}
+
 	// AI Analysis for Accumulation
 	fmt.Printf("\n🤖 AI วิเคราะห์การสะสมเหรียญใหม่...\n")
 	aiAnalyses, err := analyzeCoinsForAccumulation(bestCoins)
@@ -88,22 +145,61 @@ func main() {
 				if analysis.ReverseSignal {
 					fmt.Printf("     ⚡ มีสัญญาณกลับตัวขึ้น!\n")
 				}
+				if len(analysis.Levels.Targets) > 0 {
+					var targets []string
+					for _, target := range analysis.Levels.Targets {
+						targets = append(targets, fmt.Sprintf("$%.8f (%.1f ATR, R:R %.1f)", target.Price, target.ATRDistance, target.RewardRisk))
+					}
+					fmt.Printf("     🛑 stop $%.8f (%.1f ATR, %s) | 🎯 %s\n", analysis.Levels.Stop.Price,
+						analysis.Levels.Stop.ATRDistance, analysis.Levels.Stop.Basis, strings.Join(targets, ", "))
+				}
+				if profile := analysis.VolumeProfile; profile != nil {
+					var nodes []string
+					for _, node := range profile.HighVolumeNodes {
+						nodes = append(nodes, fmt.Sprintf("$%.8f (%.0f%%)", node.Price, node.Strength*100))
+					}
+					fmt.Printf("     📊 POC $%.8f | value area $%.8f-$%.8f | HVN: %s\n",
+						profile.POC.Price, profile.ValueAreaLow, profile.ValueAreaHigh, strings.Join(nodes, ", "))
+				}
+				if analysis.Recovery != nil {
+					fmt.Printf("     📉 หลัง listing: %s\n", analysis.Recovery.summary())
+				}
+				if len(analysis.Timeframes) > 1 {
+					var views []string
+					for _, view := range analysis.Timeframes {
+						views = append(views, fmt.Sprintf("%s %s", view.Interval, view.Trend))
+					}
+					fmt.Printf("     🕒 ไทม์เฟรมสอดคล้อง %.0f%%: %s\n", analysis.AlignmentScore, strings.Join(views, ", "))
+				}
+			}
+		}
+
+		// Targets before and after buy/sell fees
+		fmt.Printf("\n💸 เป้าหมายกำไรหลังหักค่าธรรมเนียม - %s:\n", describeFees(currentFeeSchedule()))
+		for _, analysis := range aiAnalyses {
+			var targets []string
+			for i := range analysis.ProfitTarget {
+				targets = append(targets, fmt.Sprintf("%+.1f%% (สุทธิ %+.1f%%)",
+					analysis.ProfitTargetGrossPct[i], analysis.ProfitTargetNetPct[i]))
 			}
+			fmt.Printf("   • %s: คุ้มทุนที่ $%.8f | %s\n", analysis.Symbol, analysis.BreakEven, strings.Join(targets, ", "))
 		}
 	}
 
 	// Enhanced Summary for NEW Coin Analysis
 	fmt.Printf("\n📊 สรุปการสแกนเหรียญใหม่:\n")
-	fmt.Printf("   • เป้าหมาย: เฉพาะเหรียญใหม่ (≤30 วัน)\n")
+	fmt.Printf("   • เป้าหมาย: เฉพาะเหรียญใหม่ (%d-%d วัน)\n", criteria.MinAge, criteria.MaxAge)
 	fmt.Printf("   • จำนวนสัญลักษณ์ที่วิเคราะห์: ~3,000+\n")
 	fmt.Printf("   • เหรียญใหม่ที่ผ่านเกณฑ์: %d เหรียญ\n", len(bestCoins))
 	fmt.Printf("   • คะแนนสูงสุด: %.1f\n", bestCoins[0].Score)
+	fmt.Printf("   • โมเดลคะแนน: %s\n", bestCoins[0].ScoreModel)
 	fmt.Printf("   • เหรียญใหม่ยอดนิยม: %s\n", bestCoins[0].Symbol)
 
 	fmt.Printf("\n💡 เกณฑ์การคัดเลือกเหรียญใหม่:\n")
 	fmt.Printf("   • ปริมาณขั้นต่ำ: 50K+ USDT ต่อวัน\n")
 	fmt.Printf("   • ช่วงราคา: $0.000001 - $2 (ราคาต่ำ)\n")
 	fmt.Printf("   • ช่วงการเปลี่ยนแปลง: -90%% ถึง +1000%% (ความผันผวนสูง)\n")
+	fmt.Printf("   • อายุ listing: %d-%d วัน\n", criteria.MinAge, criteria.MaxAge)
 	fmt.Printf("   • โฟกัส: เหรียญที่เข้าใหม่ (ไม่รวมเหรียญใหญ่เก่า)\n")
 	fmt.Printf("   • คะแนนขั้นต่ำ: 20+ คะแนน\n")
 
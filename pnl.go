@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CoinPnL represents FIFO profit and loss for one coin
+type CoinPnL struct {
+	Symbol        string    `json:"symbol"`
+	AgeAtBuyDays  int       `json:"ageAtBuyDays"` // listing age when first bought
+	AgeLabel      string    `json:"ageLabel"`
+	Trades        int       `json:"trades"`
+	BoughtQty     float64   `json:"boughtQty"`
+	SoldQty       float64   `json:"soldQty"`
+	HeldQty       float64   `json:"heldQty"`
+	CostBasis     float64   `json:"costBasis"` // cost of the lots still held, buy fees included
+	AvgCost       float64   `json:"avgCost"`
+	BreakEven     float64   `json:"breakEven"` // sell price that recovers AvgCost after the exit fee
+	CurrentPrice  float64   `json:"currentPrice"`
+	RealizedPnL   float64   `json:"realizedPnl"`   // net of buy and sell fees
+	UnrealizedPnL float64   `json:"unrealizedPnl"` // after the estimated taker fee to exit
+	Fees          float64   `json:"fees"`          // USDT value of all commissions
+	UnmatchedQty  float64   `json:"unmatchedQty,omitempty"`
+	FirstBuy      time.Time `json:"firstBuy"`
+}
+
+// PnLReport represents per-coin and total results
+type PnLReport struct {
+	Coins           []CoinPnL   `json:"coins"`
+	Fees            FeeSchedule `json:"fees"`
+	TotalRealized   float64     `json:"totalRealized"`
+	TotalUnrealized float64     `json:"totalUnrealized"`
+	TotalFees       float64     `json:"totalFees"`
+	GeneratedAt     time.Time   `json:"generatedAt"`
+}
+
+// costLot is an open FIFO purchase lot
+type costLot struct {
+	qty   float64
+	price float64
+}
+
+// ageBucketLabel groups a listing age into the scanner's age buckets
+func ageBucketLabel(days int) string {
+	if days <= 7 {
+		return "ใหม่มาก (≤7 วัน)"
+	} else if days <= 15 {
+		return "ใหม่ (≤15 วัน)"
+	} else if days <= 30 {
+		return "ใหม่ (≤30 วัน)"
+	}
+	return "เก่า (>30 วัน)"
+}
+
+// commissionValue converts a trade's commission to USDT
+func commissionValue(trade Trade, price float64, prices map[string]float64) float64 {
+	commission, _ := strconv.ParseFloat(trade.Commission, 64)
+	if commission == 0 {
+		return 0
+	}
+
+	switch trade.CommissionAsset {
+	case getBaseCoin(trade.Symbol):
+		// Base-asset fees are valued at the fill price
+		return commission * price
+	default:
+		// Other assets (BNB) are valued at today's price; historical prices are not stored
+		assetPrice, _ := priceInUSDT(trade.CommissionAsset, prices)
+		return commission * assetPrice
+	}
+}
+
+// calculateCoinPnL replays trades in order with FIFO lots; fees are charged to the lot or sale they belong to
+func calculateCoinPnL(symbol string, trades []Trade, currentPrice float64, prices map[string]float64, fees FeeSchedule) CoinPnL {
+	pnl := CoinPnL{Symbol: symbol, CurrentPrice: currentPrice, Trades: len(trades)}
+	baseAsset := getBaseCoin(symbol)
+
+	var lots []costLot
+	for _, trade := range trades {
+		price, _ := strconv.ParseFloat(trade.Price, 64)
+		qty, _ := strconv.ParseFloat(trade.Qty, 64)
+		commission, _ := strconv.ParseFloat(trade.Commission, 64)
+
+		fee := commissionValue(trade, price, prices)
+		pnl.Fees += fee
+
+		if trade.IsBuyer {
+			if pnl.FirstBuy.IsZero() {
+				pnl.FirstBuy = time.UnixMilli(trade.Time)
+			}
+			pnl.BoughtQty += qty
+
+			// A base-asset fee means fewer coins actually arrived; other fees add to the cost
+			received, cost := qty, qty*price
+			if trade.CommissionAsset == baseAsset {
+				received -= commission
+			} else {
+				cost += fee
+			}
+			if received > 0 {
+				lots = append(lots, costLot{qty: received, price: cost / received})
+			}
+			continue
+		}
+
+		pnl.SoldQty += qty
+		pnl.RealizedPnL -= fee
+		remaining := qty
+		for remaining > 0 && len(lots) > 0 {
+			take := min64(lots[0].qty, remaining)
+			pnl.RealizedPnL += take * (price - lots[0].price)
+			lots[0].qty -= take
+			remaining -= take
+			if lots[0].qty <= 1e-12 {
+				lots = lots[1:]
+			}
+		}
+		// Coins sold without a recorded purchase (deposits, airdrops) have no cost basis
+		pnl.UnmatchedQty += remaining
+	}
+
+	for _, lot := range lots {
+		pnl.HeldQty += lot.qty
+		pnl.CostBasis += lot.qty * lot.price
+	}
+	if pnl.HeldQty > 0 {
+		exitFee := fees.EffectiveTaker()
+		pnl.AvgCost = pnl.CostBasis / pnl.HeldQty
+		pnl.BreakEven = breakEvenPrice(pnl.AvgCost, 0, exitFee)
+		pnl.UnrealizedPnL = pnl.HeldQty*currentPrice*(1-exitFee) - pnl.CostBasis
+	}
+
+	return pnl
+}
+
+// min64 returns the smaller of two floats
+func min64(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// buildPnLReport computes PnL for every stored symbol at current prices
+func buildPnLReport(store *TradeStore, prices map[string]float64) PnLReport {
+	report := PnLReport{Fees: currentFeeSchedule(), GeneratedAt: time.Now()}
+
+	for _, symbol := range store.symbols() {
+		trades := store.Trades[symbol]
+		if len(trades) == 0 {
+			continue
+		}
+
+		pnl := calculateCoinPnL(symbol, trades, prices[symbol], prices, report.Fees)
+
+		// Listing age at first purchase = today's age minus the days since that purchase
+		if !pnl.FirstBuy.IsZero() {
+			ageNow := getCoinAgeDaysDetailed(symbol)
+			daysSinceBuy := int(time.Since(pnl.FirstBuy).Hours() / 24)
+			pnl.AgeAtBuyDays = max(0, ageNow-daysSinceBuy)
+			pnl.AgeLabel = ageBucketLabel(pnl.AgeAtBuyDays)
+		}
+
+		report.Coins = append(report.Coins, pnl)
+		report.TotalRealized += pnl.RealizedPnL
+		report.TotalUnrealized += pnl.UnrealizedPnL
+		report.TotalFees += pnl.Fees
+	}
+
+	return report
+}
+
+// printPnLReport prints the per-coin table and totals
+func printPnLReport(report PnLReport) {
+	fmt.Println("📒 กำไร/ขาดทุนรายเหรียญ (FIFO, หักค่าธรรมเนียมแล้ว):")
+	fmt.Printf("💸 ค่าธรรมเนียม: %s\n", describeFees(report.Fees))
+	fmt.Println("สัญลักษณ์     | อายุตอนซื้อ        | ถืออยู่         | ต้นทุนเฉลี่ย   | คุ้มทุน        | ราคาปัจจุบัน  | กำไรที่รับรู้  | กำไรคงค้าง    | ค่าธรรมเนียม")
+	fmt.Println("--------------|-------------------|----------------|--------------|--------------|--------------|--------------|--------------|------------")
+
+	for _, coin := range report.Coins {
+		fmt.Printf("%-13s | %-17s | %-14s | $%-11.8f | $%-11.8f | $%-11.8f | %+12.2f | %+12.2f | %10.2f\n",
+			coin.Symbol,
+			fmt.Sprintf("%d วัน %s", coin.AgeAtBuyDays, coin.AgeLabel),
+			strconv.FormatFloat(coin.HeldQty, 'f', -1, 64),
+			coin.AvgCost,
+			coin.BreakEven,
+			coin.CurrentPrice,
+			coin.RealizedPnL,
+			coin.UnrealizedPnL,
+			coin.Fees)
+		if coin.UnmatchedQty > 0 {
+			fmt.Printf("   ⚠️ %s ขาย %s โดยไม่มีประวัติซื้อ (ไม่นับต้นทุน)\n", coin.Symbol, strconv.FormatFloat(coin.UnmatchedQty, 'f', -1, 64))
+		}
+	}
+
+	fmt.Printf("\n💰 รวม: กำไรที่รับรู้ %+.2f USDT, กำไรคงค้าง %+.2f USDT, ค่าธรรมเนียม %.2f USDT\n",
+		report.TotalRealized, report.TotalUnrealized, report.TotalFees)
+}
+
+// runPnLCommand handles: pnl [--all] [SYMBOL...]
+func runPnLCommand(args []string) error {
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	all := false
+	var symbols []string
+	for _, arg := range args {
+		if arg == "--all" {
+			all = true
+			continue
+		}
+		symbols = append(symbols, arg)
+	}
+
+	store, err := syncTradeHistory(client, symbols, all)
+	if err != nil {
+		return err
+	}
+
+	prices, err := getAllPrices()
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึงราคา: %v", err)
+	}
+
+	report := buildPnLReport(store, prices)
+	if len(report.Coins) == 0 {
+		fmt.Println("❌ ไม่พบประวัติการเทรด")
+		return nil
+	}
+
+	printPnLReport(report)
+
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถแปลงเป็น JSON: %v", err)
+	}
+	fmt.Printf("\n📊 PnL Results (JSON):\n")
+	fmt.Println(string(jsonData))
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateCoinPnL(t *testing.T) {
+	fees := FeeSchedule{Maker: 0.001, Taker: 0.001}
+	trade := func(buy bool, price, qty, commission, asset string) Trade {
+		return Trade{Symbol: "ABCUSDT", Price: price, Qty: qty, Commission: commission, CommissionAsset: asset, IsBuyer: buy}
+	}
+
+	tests := []struct {
+		name         string
+		trades       []Trade
+		currentPrice float64
+		wantRealized float64
+		wantHeld     float64
+		wantCost     float64
+		wantUnreal   float64
+		wantFees     float64
+		wantUnmatch  float64
+	}{
+		{
+			name: "sale consumes the oldest lot first",
+			trades: []Trade{
+				trade(true, "1", "10", "0.01", "USDT"),
+				trade(true, "2", "10", "0.02", "USDT"),
+				trade(false, "3", "15", "0.045", "USDT"),
+			},
+			currentPrice: 4,
+			// 10 × (3 − 1.001) + 5 × (3 − 2.002) − 0.045
+			wantRealized: 19.99 + 4.99 - 0.045,
+			wantHeld:     5,
+			wantCost:     10.01,
+			wantUnreal:   5*4*0.999 - 10.01,
+			wantFees:     0.075,
+		},
+		{
+			name:         "base-asset fee shrinks the lot instead of adding cost",
+			trades:       []Trade{trade(true, "1", "10", "0.1", "ABC")},
+			currentPrice: 1,
+			wantHeld:     9.9,
+			wantCost:     10,
+			wantUnreal:   9.9*0.999 - 10,
+			wantFees:     0.1,
+		},
+		{
+			name: "fully sold coin leaves nothing held",
+			trades: []Trade{
+				trade(true, "1", "10", "0", "USDT"),
+				trade(false, "0.5", "10", "0", "USDT"),
+			},
+			currentPrice: 2,
+			wantRealized: -5,
+		},
+		{
+			name:         "sale without a recorded buy is unmatched",
+			trades:       []Trade{trade(false, "2", "5", "0", "USDT")},
+			currentPrice: 2,
+			wantUnmatch:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateCoinPnL("ABCUSDT", tt.trades, tt.currentPrice, nil, fees)
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"RealizedPnL", got.RealizedPnL, tt.wantRealized},
+				{"HeldQty", got.HeldQty, tt.wantHeld},
+				{"CostBasis", got.CostBasis, tt.wantCost},
+				{"UnrealizedPnL", got.UnrealizedPnL, tt.wantUnreal},
+				{"Fees", got.Fees, tt.wantFees},
+				{"UnmatchedQty", got.UnmatchedQty, tt.wantUnmatch},
+			}
+			for _, c := range checks {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Default value below which an asset is grouped as dust
+const defaultDustThreshold = 1.0
+
+// PortfolioEntry represents one asset's balance and its USDT value
+type PortfolioEntry struct {
+	Asset      string  `json:"asset"`
+	Free       float64 `json:"free"`
+	Locked     float64 `json:"locked"`
+	Total      float64 `json:"total"`
+	Price      float64 `json:"price"` // USDT per unit, 0 when no market was found
+	Value      float64 `json:"value"`
+	Allocation float64 `json:"allocation"` // percent of total equity
+}
+
+// Portfolio represents the valued account, largest holdings first
+type Portfolio struct {
+	Entries     []PortfolioEntry `json:"entries"`
+	Dust        []PortfolioEntry `json:"dust"`
+	DustValue   float64          `json:"dustValue"`
+	Unpriced    []PortfolioEntry `json:"unpriced"`
+	TotalEquity float64          `json:"totalEquity"`
+}
+
+// priceInUSDT finds an asset's USDT price directly, through an inverted pair, or via BTC
+func priceInUSDT(asset string, prices map[string]float64) (float64, bool) {
+	if asset == "USDT" {
+		return 1, true
+	}
+	if price, ok := prices[asset+"USDT"]; ok && price > 0 {
+		return price, true
+	}
+	if price, ok := prices["USDT"+asset]; ok && price > 0 {
+		return 1 / price, true
+	}
+	if btcPrice, ok := prices[asset+"BTC"]; ok && btcPrice > 0 {
+		if btcUSDT, ok := prices["BTCUSDT"]; ok {
+			return btcPrice * btcUSDT, true
+		}
+	}
+	return 0, false
+}
+
+// buildPortfolio values every balance and groups everything below dustThreshold
+func buildPortfolio(balances []AssetBalance, prices map[string]float64, dustThreshold float64) Portfolio {
+	var portfolio Portfolio
+	var valued []PortfolioEntry
+
+	for _, balance := range balances {
+		entry := PortfolioEntry{
+			Asset:  balance.Asset,
+			Free:   balance.Free,
+			Locked: balance.Locked,
+			Total:  balance.Free + balance.Locked,
+		}
+
+		price, ok := priceInUSDT(balance.Asset, prices)
+		if !ok {
+			portfolio.Unpriced = append(portfolio.Unpriced, entry)
+			continue
+		}
+		entry.Price = price
+		entry.Value = entry.Total * price
+		portfolio.TotalEquity += entry.Value
+		valued = append(valued, entry)
+	}
+
+	sort.Slice(valued, func(i, j int) bool {
+		return valued[i].Value > valued[j].Value
+	})
+
+	for _, entry := range valued {
+		if portfolio.TotalEquity > 0 {
+			entry.Allocation = entry.Value / portfolio.TotalEquity * 100
+		}
+		if entry.Value < dustThreshold {
+			portfolio.Dust = append(portfolio.Dust, entry)
+			portfolio.DustValue += entry.Value
+			continue
+		}
+		portfolio.Entries = append(portfolio.Entries, entry)
+	}
+
+	return portfolio
+}
+
+// getPortfolio loads balances and prices and values the whole account
+func getPortfolio(client *BinanceClient, dustThreshold float64) (*Portfolio, error) {
+	balances, err := getAccountBalances(client)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึงยอดเงิน: %v", err)
+	}
+
+	prices, err := getAllPrices()
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึงราคา: %v", err)
+	}
+
+	portfolio := buildPortfolio(balances, prices, dustThreshold)
+	return &portfolio, nil
+}
+
+// printPortfolio prints the allocation table and total equity
+func printPortfolio(portfolio *Portfolio, dustThreshold float64) {
+	fmt.Println("💼 พอร์ตโฟลิโอ:")
+	fmt.Println("สินทรัพย์   | คงเหลือ           | ถูกล็อก           | รวม               | ราคา (USDT)   | มูลค่า (USDT) | สัดส่วน")
+	fmt.Println("-----------|-------------------|-------------------|-------------------|---------------|--------------|--------")
+
+	for _, entry := range portfolio.Entries {
+		fmt.Printf("%-10s | %-17s | %-17s | %-17s | %-13.8f | %12.2f | %6.2f%%\n",
+			entry.Asset,
+			strconv.FormatFloat(entry.Free, 'f', -1, 64),
+			strconv.FormatFloat(entry.Locked, 'f', -1, 64),
+			strconv.FormatFloat(entry.Total, 'f', -1, 64),
+			entry.Price,
+			entry.Value,
+			entry.Allocation)
+	}
+
+	if len(portfolio.Dust) > 0 {
+		dustAllocation := 0.0
+		if portfolio.TotalEquity > 0 {
+			dustAllocation = portfolio.DustValue / portfolio.TotalEquity * 100
+		}
+		fmt.Printf("%-10s | %-17s | %-17s | %-17s | %-13s | %12.2f | %6.2f%%\n",
+			fmt.Sprintf("dust (%d)", len(portfolio.Dust)), "", "", "", fmt.Sprintf("< $%.2f", dustThreshold),
+			portfolio.DustValue, dustAllocation)
+	}
+
+	fmt.Printf("\n💰 มูลค่ารวม: $%.2f\n", portfolio.TotalEquity)
+
+	if len(portfolio.Unpriced) > 0 {
+		fmt.Printf("⚠️ ไม่พบราคา USDT สำหรับ:")
+		for _, entry := range portfolio.Unpriced {
+			fmt.Printf(" %s (%s)", entry.Asset, strconv.FormatFloat(entry.Total, 'f', -1, 64))
+		}
+		fmt.Println()
+	}
+}
+
+// runPortfolioCommand handles: portfolio [DUST_THRESHOLD]
+func runPortfolioCommand(args []string) error {
+	dustThreshold := defaultDustThreshold
+	if len(args) > 0 {
+		value, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return fmt.Errorf("ค่า dust threshold ไม่ถูกต้อง: %s", args[0])
+		}
+		dustThreshold = value
+	}
+
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	portfolio, err := getPortfolio(client, dustThreshold)
+	if err != nil {
+		return err
+	}
+
+	printPortfolio(portfolio, dustThreshold)
+	return nil
+}
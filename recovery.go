@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// Post-listing phases
+const (
+	phaseNone       = "none"       // never dumped far enough to be a recovery setup
+	phaseDumping    = "dumping"    // the low is still fresh
+	phaseBasing     = "basing"     // holding near the low
+	phaseRecovering = "recovering" // bounced clear of the base
+	phaseFailed     = "failed"     // bounced, then gave it back
+)
+
+const (
+	// Hours after the first candle that count as the listing spike
+	listingWindowHours = 72
+	// Most candles a single klines request returns
+	maxKlinesPerRequest = 1000
+)
+
+// RecoveryConfig represents the dump-and-recovery thresholds loaded from .env
+type RecoveryConfig struct {
+	MinDrawdown  float64  // RECOVERY_MIN_DRAWDOWN: percent below the listing high that counts as a dump
+	BaseBand     float64  // RECOVERY_BASE_BAND: closes within this percent above the low are basing
+	MinBaseHours float64  // RECOVERY_MIN_BASE_HOURS: time since the low before a dump is considered over
+	MinBounce    float64  // RECOVERY_MIN_BOUNCE: percent above the low that counts as recovering
+	Phases       []string // RECOVERY_PHASES: phases RequireRecovery keeps
+}
+
+// RecoveryProfile represents the post-listing dump, base and bounce of a coin
+type RecoveryProfile struct {
+	Phase           string    `json:"phase"` // none, dumping, basing, recovering, failed
+	Interval        string    `json:"interval"`
+	ListingHigh     float64   `json:"listingHigh"`
+	ListingHighTime time.Time `json:"listingHighTime"`
+	Low             float64   `json:"low"` // lowest low after the listing high
+	LowTime         time.Time `json:"lowTime"`
+	DrawdownPercent float64   `json:"drawdownPercent"` // low vs listing high
+	BaseHours       float64   `json:"baseHours"`       // time closes stayed within the base band after the low
+	HoursSinceLow   float64   `json:"hoursSinceLow"`
+	BouncePercent   float64   `json:"bouncePercent"`     // last close vs low
+	PeakBounce      float64   `json:"peakBouncePercent"` // best close since the low vs low
+	RetracePercent  float64   `json:"retracePercent"`    // share of the dump won back
+}
+
+// loadRecoveryConfig reads the dump and recovery thresholds and the phases RequireRecovery keeps
+func loadRecoveryConfig() RecoveryConfig {
+	defaults := []string{phaseBasing, phaseRecovering}
+	phases := defaults
+	if raw := os.Getenv("RECOVERY_PHASES"); raw != "" {
+		phases = nil
+		for _, phase := range strings.Split(raw, ",") {
+			phase = strings.ToLower(strings.TrimSpace(phase))
+			if phase == "" {
+				continue
+			}
+			if !isRecoveryPhase(phase) {
+				fmt.Printf("⚠️ RECOVERY_PHASES: %q ไม่ถูกต้อง - ใช้ค่าเริ่มต้น %s\n", phase, strings.Join(defaults, ","))
+				phases = defaults
+				break
+			}
+			phases = append(phases, phase)
+		}
+		if len(phases) == 0 {
+			phases = defaults
+		}
+	}
+
+	return RecoveryConfig{
+		MinDrawdown:  envFloat("RECOVERY_MIN_DRAWDOWN", 30),
+		BaseBand:     envFloat("RECOVERY_BASE_BAND", 15),
+		MinBaseHours: envFloat("RECOVERY_MIN_BASE_HOURS", 24),
+		MinBounce:    envFloat("RECOVERY_MIN_BOUNCE", 20),
+		Phases:       phases,
+	}
+}
+
+// isRecoveryPhase reports whether phase is one detectRecovery can produce
+func isRecoveryPhase(phase string) bool {
+	switch phase {
+	case phaseNone, phaseDumping, phaseBasing, phaseRecovering, phaseFailed:
+		return true
+	}
+	return false
+}
+
+// accepts reports whether RequireRecovery lets a phase through
+func (c RecoveryConfig) accepts(phase string) bool {
+	for _, allowed := range c.Phases {
+		if allowed == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// getListingHistory returns klines from the first traded candle, on the finest of 1h, 4h
+// and 1d that fits the whole history into one request. When ageDays underestimates the
+// history and even 1d candles from the listing stop short of now, it returns the latest
+// candles instead so levels always reflect current prices
+func getListingHistory(symbol string, ageDays int) ([]Kline, string, error) {
+	candidates := []string{"1h", "4h", "1d"}
+	for len(candidates) > 1 {
+		duration, _ := intervalDuration(candidates[0])
+		if float64(ageDays+1)*24 <= float64(maxKlinesPerRequest)*duration.Hours() {
+			break
+		}
+		candidates = candidates[1:]
+	}
+
+	for _, interval := range candidates {
+		klines, err := getKlinesFrom(&BinanceClient{}, symbol, interval, 0, maxKlinesPerRequest)
+		if err != nil {
+			return nil, "", fmt.Errorf("ไม่สามารถดึงข้อมูลหลัง listing %s: %v", symbol, err)
+		}
+		if len(klines) == 0 {
+			return nil, "", fmt.Errorf("ไม่พบข้อมูลหลัง listing %s", symbol)
+		}
+		if reachesNow(klines, interval, time.Now()) {
+			return klines, interval, nil
+		}
+	}
+
+	// Longer than one request even on 1d: anchor the window to now
+	klines, err := getKlines(&BinanceClient{}, symbol, "1d", maxKlinesPerRequest)
+	if err != nil {
+		return nil, "", fmt.Errorf("ไม่สามารถดึงข้อมูลหลัง listing %s: %v", symbol, err)
+	}
+	if len(klines) == 0 {
+		return nil, "", fmt.Errorf("ไม่พบข้อมูลหลัง listing %s", symbol)
+	}
+	return klines, "1d", nil
+}
+
+// reachesNow reports whether the klines run up to the current candle rather than
+// stopping at the request limit
+func reachesNow(klines []Kline, interval string, now time.Time) bool {
+	if len(klines) < maxKlinesPerRequest {
+		return true
+	}
+	duration, err := intervalDuration(interval)
+	if err != nil {
+		return true
+	}
+	return time.UnixMilli(klines[len(klines)-1].CloseTime).Add(duration).After(now)
+}
+
+// detectRecovery measures the listing high, the post-listing low and what happened up to now;
+// klines should start at the listing, a history anchored to now takes its first candles as the spike
+func detectRecovery(klines []Kline, interval string, config RecoveryConfig, now time.Time) RecoveryProfile {
+	profile := RecoveryProfile{Phase: phaseNone, Interval: interval}
+	if len(klines) == 0 {
+		return profile
+	}
+	duration, err := intervalDuration(interval)
+	if err != nil {
+		return profile
+	}
+	candleHours := duration.Hours()
+
+	// Listing high: highest high during the listing window
+	window := max(1, int(math.Ceil(listingWindowHours/candleHours)))
+	highIndex := 0
+	for i := 1; i < min(window, len(klines)); i++ {
+		if klines[i].High > klines[highIndex].High {
+			highIndex = i
+		}
+	}
+
+	// Post-listing low: lowest low from the listing high onwards
+	lowIndex := highIndex
+	for i := highIndex; i < len(klines); i++ {
+		if klines[i].Low < klines[lowIndex].Low {
+			lowIndex = i
+		}
+	}
+
+	high, low := klines[highIndex].High, klines[lowIndex].Low
+	lastClose := klines[len(klines)-1].Close
+	profile.ListingHigh = high
+	profile.ListingHighTime = time.UnixMilli(klines[highIndex].OpenTime)
+	profile.Low = low
+	profile.LowTime = time.UnixMilli(klines[lowIndex].OpenTime)
+	profile.HoursSinceLow = math.Max(0, now.Sub(profile.LowTime).Hours())
+	if high <= 0 || low <= 0 {
+		return profile
+	}
+
+	profile.DrawdownPercent = (high - low) / high * 100
+	profile.BouncePercent = (lastClose - low) / low * 100
+	if high > low {
+		profile.RetracePercent = (lastClose - low) / (high - low) * 100
+	}
+
+	baseTop := low * (1 + config.BaseBand/100)
+	peak := low
+	basing := true
+	for _, k := range klines[lowIndex+1:] {
+		if basing && k.Close <= baseTop {
+			profile.BaseHours += candleHours
+		} else {
+			basing = false
+		}
+		peak = math.Max(peak, k.Close)
+	}
+	profile.PeakBounce = (peak - low) / low * 100
+
+	switch {
+	case profile.DrawdownPercent < config.MinDrawdown:
+		profile.Phase = phaseNone
+	case profile.HoursSinceLow < config.MinBaseHours:
+		profile.Phase = phaseDumping
+	case profile.BouncePercent >= config.MinBounce:
+		profile.Phase = phaseRecovering
+	case profile.PeakBounce >= config.MinBounce && profile.BouncePercent < config.MinBounce/2:
+		profile.Phase = phaseFailed
+	default:
+		profile.Phase = phaseBasing
+	}
+	return profile
+}
+
+// analyzeRecovery fetches the history since listing and classifies it
+func analyzeRecovery(symbol string, ageDays int, config RecoveryConfig) (*RecoveryProfile, error) {
+	klines, interval, err := getListingHistory(symbol, ageDays)
+	if err != nil {
+		return nil, err
+	}
+	profile := detectRecovery(klines, interval, config, time.Now())
+	return &profile, nil
+}
+
+// recoveryPhaseLabel returns the Thai label of a phase
+func recoveryPhaseLabel(phase string) string {
+	switch phase {
+	case phaseDumping:
+		return "กำลังเทขาย"
+	case phaseBasing:
+		return "สร้างฐาน"
+	case phaseRecovering:
+		return "กำลังฟื้นตัว"
+	case phaseFailed:
+		return "ฟื้นตัวล้มเหลว"
+	}
+	return "ไม่มีการเทขายหลัง listing"
+}
+
+// summary describes the profile in one Thai line
+func (p RecoveryProfile) summary() string {
+	if p.Phase == phaseNone {
+		return fmt.Sprintf("%s (ลงจากจุดสูงสุด %.0f%%)", recoveryPhaseLabel(p.Phase), p.DrawdownPercent)
+	}
+	return fmt.Sprintf("%s: ลงจากจุดสูงสุดหลัง listing %.0f%%, ฐาน %.0f ชม., เด้งจากจุดต่ำสุด %+.0f%%",
+		recoveryPhaseLabel(p.Phase), p.DrawdownPercent, p.BaseHours, p.BouncePercent)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// hourlyKlines builds 1h candles that open, close and range at each price
+func hourlyKlines(start time.Time, prices []float64) []Kline {
+	klines := make([]Kline, len(prices))
+	for i, price := range prices {
+		open := start.Add(time.Duration(i) * time.Hour)
+		klines[i] = Kline{
+			OpenTime:  open.UnixMilli(),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+			CloseTime: open.Add(time.Hour).UnixMilli() - 1,
+		}
+	}
+	return klines
+}
+
+// dumpThen lists at 100, dumps to 50 by the 10th hour and continues with after
+func dumpThen(after ...float64) []float64 {
+	prices := []float64{100, 95, 90, 85, 80, 75, 70, 65, 60, 55, 50}
+	return append(prices, after...)
+}
+
+func repeat(price float64, n int) []float64 {
+	prices := make([]float64, n)
+	for i := range prices {
+		prices[i] = price
+	}
+	return prices
+}
+
+func TestDetectRecovery(t *testing.T) {
+	config := RecoveryConfig{MinDrawdown: 30, BaseBand: 15, MinBaseHours: 24, MinBounce: 20}
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		prices        []float64
+		wantPhase     string
+		wantDrawdown  float64
+		wantBounce    float64
+		wantBaseHours float64
+		wantSinceLow  float64
+		gap           time.Duration // between the last candle's close and now
+	}{
+		{
+			name:          "shallow pullback is not a dump",
+			prices:        append([]float64{100}, repeat(80, 40)...),
+			wantPhase:     phaseNone,
+			wantDrawdown:  20,
+			wantBaseHours: 39,
+			wantSinceLow:  40,
+		},
+		{
+			name:          "fresh low is still dumping",
+			prices:        dumpThen(repeat(52, 10)...),
+			wantPhase:     phaseDumping,
+			wantDrawdown:  50,
+			wantBounce:    4,
+			wantBaseHours: 10,
+			wantSinceLow:  11,
+		},
+		{
+			name:          "wall time since the low counts when the history stops short",
+			prices:        dumpThen(repeat(52, 10)...),
+			gap:           30 * time.Hour,
+			wantPhase:     phaseBasing,
+			wantDrawdown:  50,
+			wantBounce:    4,
+			wantBaseHours: 10,
+			wantSinceLow:  41,
+		},
+		{
+			name:          "holding near the low is basing",
+			prices:        dumpThen(repeat(52, 40)...),
+			wantPhase:     phaseBasing,
+			wantDrawdown:  50,
+			wantBounce:    4,
+			wantBaseHours: 40,
+			wantSinceLow:  41,
+		},
+		{
+			name:          "clearing the base is recovering",
+			prices:        dumpThen(append(repeat(52, 30), 60, 65)...),
+			wantPhase:     phaseRecovering,
+			wantDrawdown:  50,
+			wantBounce:    30,
+			wantBaseHours: 30,
+			wantSinceLow:  33,
+		},
+		{
+			name:          "giving the bounce back is failed",
+			prices:        dumpThen(append(repeat(52, 30), 65, 52)...),
+			wantPhase:     phaseFailed,
+			wantDrawdown:  50,
+			wantBounce:    4,
+			wantBaseHours: 30,
+			wantSinceLow:  33,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start.Add(time.Duration(len(tt.prices))*time.Hour + tt.gap)
+			got := detectRecovery(hourlyKlines(start, tt.prices), "1h", config, now)
+			if got.Phase != tt.wantPhase {
+				t.Errorf("phase = %s, want %s", got.Phase, tt.wantPhase)
+			}
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"DrawdownPercent", got.DrawdownPercent, tt.wantDrawdown},
+				{"BouncePercent", got.BouncePercent, tt.wantBounce},
+				{"BaseHours", got.BaseHours, tt.wantBaseHours},
+				{"HoursSinceLow", got.HoursSinceLow, tt.wantSinceLow},
+			}
+			for _, c := range checks {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+
+	if got := detectRecovery(nil, "1h", config, start); got.Phase != phaseNone {
+		t.Errorf("phase without klines = %s, want %s", got.Phase, phaseNone)
+	}
+}
+
+func TestLoadRecoveryConfigPhases(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{"unset keeps the default", "", []string{phaseBasing, phaseRecovering}},
+		{"known phases are kept", " Dumping, basing ", []string{phaseDumping, phaseBasing}},
+		{"unknown phase falls back to the default", "basing,recovred", []string{phaseBasing, phaseRecovering}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RECOVERY_PHASES", tt.raw)
+			got := loadRecoveryConfig().Phases
+			if len(got) != len(tt.want) {
+				t.Fatalf("phases = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("phases = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReachesNow(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	full := hourlyKlines(start, repeat(1, maxKlinesPerRequest))
+	end := start.Add(maxKlinesPerRequest * time.Hour)
+
+	tests := []struct {
+		name   string
+		klines []Kline
+		now    time.Time
+		want   bool
+	}{
+		{"short history is complete", full[:10], end.Add(24 * time.Hour), true},
+		{"full request ending at the current candle", full, end.Add(30 * time.Minute), true},
+		{"full request stopping in the past", full, end.Add(48 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reachesNow(tt.klines, "1h", tt.now); got != tt.want {
+				t.Errorf("reachesNow = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RiskConfig represents the pre-trade limits loaded from .env
+type RiskConfig struct {
+	MaxOrders        int     // MAX_ORDERS: resting orders allowed across all symbols, grid orders excluded
+	MinBalance       float64 // MIN_BALANCE: USDT that must stay free after a buy
+	RiskPercentage   float64 // RISK_PERCENTAGE: equity lost when a position hits its stop
+	PositionSize     float64 // POSITION_SIZE: max USDT exposure per coin
+	MaxTotalExposure float64 // MAX_TOTAL_EXPOSURE: max percent of equity held in coins
+}
+
+// RiskError represents an order rejected by the risk manager
+type RiskError struct {
+	Rule   string
+	Reason string
+}
+
+// Error describes the rule that rejected the order
+func (e *RiskError) Error() string {
+	return fmt.Sprintf("ถูกปฏิเสธโดย risk manager [%s]: %s", e.Rule, e.Reason)
+}
+
+// loadRiskConfig reads the risk limits, using the .env.example defaults when unset
+func loadRiskConfig() RiskConfig {
+	return RiskConfig{
+		MaxOrders:        envInt("MAX_ORDERS", 3),
+		MinBalance:       envFloat("MIN_BALANCE", 10),
+		RiskPercentage:   envFloat("RISK_PERCENTAGE", 2),
+		PositionSize:     envFloat("POSITION_SIZE", 50),
+		MaxTotalExposure: envFloat("MAX_TOTAL_EXPOSURE", 50),
+	}
+}
+
+// countedOpenOrders returns the open orders MAX_ORDERS applies to; the grid's levels are bounded by its own grid count
+func countedOpenOrders(orders []OrderStatus) int {
+	count := 0
+	for _, order := range orders {
+		if orderStrategy(order.ClientOrderID) != strategyGrid {
+			count++
+		}
+	}
+	return count
+}
+
+// openBuyNotional returns the USDT still committed to open BUY orders, per base asset
+func openBuyNotional(orders []OrderStatus) map[string]float64 {
+	committed := map[string]float64{}
+	for _, order := range orders {
+		if order.Side != "BUY" || !strings.HasSuffix(order.Symbol, "USDT") {
+			continue
+		}
+		price, _ := strconv.ParseFloat(order.Price, 64)
+		orig, _ := strconv.ParseFloat(order.OrigQty, 64)
+		executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+		if remaining := orig - executed; remaining > 0 && price > 0 {
+			committed[getBaseCoin(order.Symbol)] += remaining * price
+		}
+	}
+	return committed
+}
+
+// positionStop returns the stop a BUY is sized against: the tag's own stop, or the active
+// trailing stop on the symbol; 0 when the position has no stop
+func positionStop(tag OrderTag, symbol string) (float64, error) {
+	if tag.Stop > 0 {
+		return tag.Stop, nil
+	}
+	state, err := loadTrailingState()
+	if err != nil {
+		return 0, err
+	}
+	if stop, ok := state.Stops[symbol]; ok && stop.Status == "ACTIVE" {
+		return stop.StopPrice, nil
+	}
+	return 0, nil
+}
+
+// checkOrderRisk validates an order against the per-order limits before it is sent.
+// BUYs into a position with a stop are also held to RISK_PERCENTAGE
+func checkOrderRisk(client *BinanceClient, tag OrderTag, symbol, side, orderType, quantity, price string) error {
+	config := loadRiskConfig()
+
+	qty, err := strconv.ParseFloat(quantity, 64)
+	if err != nil || qty <= 0 {
+		return &RiskError{Rule: "QUANTITY", Reason: fmt.Sprintf("จำนวนไม่ถูกต้อง %q", quantity)}
+	}
+
+	// Market sells only reduce exposure, so exits are never blocked
+	if side == "SELL" && orderType == "MARKET" {
+		return nil
+	}
+
+	// A tripped kill switch halts everything else until a human resets it
+	if err := killSwitchBlock(); err != nil {
+		return err
+	}
+
+	checkMaxOrders := orderType == "LIMIT" && config.MaxOrders > 0 && tag.Strategy != strategyGrid
+	if !checkMaxOrders && side != "BUY" {
+		return nil
+	}
+
+	openOrders, err := getOpenOrders(client, "")
+	if err != nil {
+		return fmt.Errorf("risk manager: ไม่สามารถดึง open orders: %v", err)
+	}
+	if checkMaxOrders {
+		if count := countedOpenOrders(openOrders); count >= config.MaxOrders {
+			return &RiskError{Rule: "MAX_ORDERS", Reason: fmt.Sprintf("มี open orders %d/%d แล้ว (ไม่นับกริด)", count, config.MaxOrders)}
+		}
+	}
+
+	if side != "BUY" {
+		return nil
+	}
+
+	orderPrice, _ := strconv.ParseFloat(price, 64)
+	if orderPrice <= 0 {
+		orderPrice, err = getCurrentPriceForSymbol(client, symbol)
+		if err != nil {
+			return fmt.Errorf("risk manager: ไม่สามารถดึงราคา %s: %v", symbol, err)
+		}
+	}
+	notional := qty * orderPrice
+
+	stop, err := positionStop(tag, symbol)
+	if err != nil {
+		return fmt.Errorf("risk manager: %v", err)
+	}
+
+	portfolio, err := getPortfolio(client, 0)
+	if err != nil {
+		return fmt.Errorf("risk manager: %v", err)
+	}
+
+	return evaluateBuyRisk(config, portfolio, openBuyNotional(openOrders), getBaseCoin(symbol), notional, orderPrice, stop)
+}
+
+// evaluateBuyRisk checks the USDT reserve, per-coin exposure and total coin exposure, counting USDT
+// committed to open BUY orders as exposure. With a stop, the loss of the whole coin position at the
+// stop must stay within RISK_PERCENTAGE of equity
+func evaluateBuyRisk(config RiskConfig, portfolio *Portfolio, openBuys map[string]float64, asset string, notional, price, stop float64) error {
+	freeUSDT, coinExposure, totalExposure := 0.0, openBuys[asset], 0.0
+	for _, committed := range openBuys {
+		totalExposure += committed
+	}
+	for _, entry := range append(portfolio.Entries, portfolio.Dust...) {
+		if entry.Asset == "USDT" {
+			freeUSDT = entry.Free
+			continue
+		}
+		totalExposure += entry.Value
+		if entry.Asset == asset {
+			coinExposure += entry.Value
+		}
+	}
+
+	if freeUSDT-notional < config.MinBalance {
+		return &RiskError{Rule: "MIN_BALANCE", Reason: fmt.Sprintf(
+			"USDT คงเหลือหลังซื้อ %.2f ต่ำกว่าขั้นต่ำ %.2f", freeUSDT-notional, config.MinBalance)}
+	}
+
+	if config.PositionSize > 0 && coinExposure+notional > config.PositionSize {
+		return &RiskError{Rule: "POSITION_SIZE", Reason: fmt.Sprintf(
+			"%s จะมีมูลค่า %.2f USDT เกินเพดานต่อเหรียญ %.2f USDT", asset, coinExposure+notional, config.PositionSize)}
+	}
+
+	if config.MaxTotalExposure > 0 && portfolio.TotalEquity > 0 {
+		limit := portfolio.TotalEquity * config.MaxTotalExposure / 100
+		if totalExposure+notional > limit {
+			return &RiskError{Rule: "MAX_TOTAL_EXPOSURE", Reason: fmt.Sprintf(
+				"มูลค่าเหรียญรวมจะเป็น %.2f USDT เกิน %.0f%% ของพอร์ต (%.2f USDT)", totalExposure+notional, config.MaxTotalExposure, limit)}
+		}
+	}
+
+	if stop > 0 && config.RiskPercentage > 0 && portfolio.TotalEquity > 0 {
+		if stop >= price {
+			return &RiskError{Rule: "STOP_LOSS", Reason: fmt.Sprintf("stop loss $%.8f ต้องต่ำกว่าราคาซื้อ $%.8f", stop, price)}
+		}
+		// Everything held or bid for in the coin is assumed bought at this price
+		loss := (coinExposure + notional) * (price - stop) / price
+		limit := portfolio.TotalEquity * config.RiskPercentage / 100
+		if loss > limit {
+			return &RiskError{Rule: "RISK_PERCENTAGE", Reason: fmt.Sprintf(
+				"ขาดทุนถ้าโดน stop $%.8f จะเป็น %.2f USDT เกิน %.1f%% ของพอร์ต (%.2f USDT)", stop, loss, config.RiskPercentage, limit)}
+		}
+	}
+
+	return nil
+}
+
+// calculateRiskPositionSize sizes a position so a stop-out loses at most RISK_PERCENTAGE of equity
+func calculateRiskPositionSize(config RiskConfig, equity, entryPrice, stopLoss float64) (float64, error) {
+	if entryPrice <= 0 || stopLoss <= 0 || stopLoss >= entryPrice {
+		return 0, &RiskError{Rule: "STOP_LOSS", Reason: fmt.Sprintf(
+			"stop loss $%.8f ต้องต่ำกว่าราคาเข้า $%.8f", stopLoss, entryPrice)}
+	}
+
+	riskAmount := equity * config.RiskPercentage / 100
+	quantity := riskAmount / (entryPrice - stopLoss)
+
+	// Never exceed the per-coin cap even when the stop is very tight
+	if config.PositionSize > 0 && quantity*entryPrice > config.PositionSize {
+		quantity = config.PositionSize / entryPrice
+	}
+
+	return quantity, nil
+}
+
+// printRiskStatus shows the configured limits and current usage
+func printRiskStatus(client *BinanceClient, config RiskConfig) error {
+	portfolio, err := getPortfolio(client, 0)
+	if err != nil {
+		return err
+	}
+	openOrders, err := getOpenOrders(client, "")
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึง open orders: %v", err)
+	}
+
+	freeUSDT, totalExposure := 0.0, 0.0
+	for _, entry := range append(portfolio.Entries, portfolio.Dust...) {
+		if entry.Asset == "USDT" {
+			freeUSDT = entry.Free
+			continue
+		}
+		totalExposure += entry.Value
+	}
+
+	exposurePercent := 0.0
+	if portfolio.TotalEquity > 0 {
+		exposurePercent = totalExposure / portfolio.TotalEquity * 100
+	}
+
+	fmt.Println("🛡️ Risk Manager:")
+	fmt.Printf("   • Open orders: %d/%d (MAX_ORDERS, ไม่นับกริด %d)\n",
+		countedOpenOrders(openOrders), config.MaxOrders, len(openOrders)-countedOpenOrders(openOrders))
+	fmt.Printf("   • USDT ว่าง: %.2f (สำรองขั้นต่ำ %.2f, MIN_BALANCE)\n", freeUSDT, config.MinBalance)
+	fmt.Printf("   • เพดานต่อเหรียญ: %.2f USDT (POSITION_SIZE)\n", config.PositionSize)
+	fmt.Printf("   • มูลค่าเหรียญรวม: %.2f USDT = %.1f%% ของพอร์ต (เพดาน %.0f%%, MAX_TOTAL_EXPOSURE)\n",
+		totalExposure, exposurePercent, config.MaxTotalExposure)
+	fmt.Printf("   • ความเสี่ยงต่อไม้: %.2f%% ของพอร์ต = %.2f USDT (RISK_PERCENTAGE)\n",
+		config.RiskPercentage, portfolio.TotalEquity*config.RiskPercentage/100)
+	return nil
+}
+
+// runRiskCommand handles: risk | risk size SYMBOL [STOP_LOSS]
+func runRiskCommand(args []string) error {
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+	config := loadRiskConfig()
+
+	if len(args) == 0 {
+		return printRiskStatus(client, config)
+	}
+	if args[0] != "size" || len(args) < 2 {
+		return errors.New("ใช้งาน: risk | risk size SYMBOL [STOP_LOSS]")
+	}
+
+	symbol := strings.ToUpper(args[1])
+	price, err := getCurrentPriceForSymbol(client, symbol)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึงราคา %s: %v", symbol, err)
+	}
+
+	stopLoss := 0.0
+	if len(args) > 2 {
+		stopLoss, err = strconv.ParseFloat(args[2], 64)
+		if err != nil {
+			return fmt.Errorf("stop loss ไม่ถูกต้อง: %s", args[2])
+		}
+	} else {
+		// Use the analysis stop loss for the coin
+		analysis, err := analyzeSymbol(client, symbol)
+		if err != nil {
+			return fmt.Errorf("ไม่สามารถวิเคราะห์ %s เพื่อหา stop loss: %v", symbol, err)
+		}
+		stopLoss = analysis.StopLoss
+	}
+
+	portfolio, err := getPortfolio(client, 0)
+	if err != nil {
+		return err
+	}
+	if portfolio.TotalEquity <= 0 {
+		return fmt.Errorf("มูลค่าพอร์ตเป็นศูนย์")
+	}
+
+	quantity, err := calculateRiskPositionSize(config, portfolio.TotalEquity, price, stopLoss)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📐 ขนาด position สำหรับ %s:\n", symbol)
+	fmt.Printf("   • ราคาเข้า: $%.8f, stop loss: $%.8f (%.1f%%)\n", price, stopLoss, (price-stopLoss)/price*100)
+	fmt.Printf("   • จำนวน: %s (%.2f USDT)\n", strconv.FormatFloat(quantity, 'f', -1, 64), quantity*price)
+	fmt.Printf("   • ขาดทุนถ้าโดน stop: %.2f USDT (%.2f%% ของพอร์ต)\n",
+		quantity*(price-stopLoss), quantity*(price-stopLoss)/portfolio.TotalEquity*100)
+	return nil
+}
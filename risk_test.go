@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestOpenBuyNotional(t *testing.T) {
+	orders := []OrderStatus{
+		{Symbol: "AAAUSDT", Side: "BUY", Price: "2", OrigQty: "10", ExecutedQty: "4"},
+		{Symbol: "AAAUSDT", Side: "BUY", Price: "1", OrigQty: "5", ExecutedQty: "0"},
+		{Symbol: "AAAUSDT", Side: "SELL", Price: "3", OrigQty: "10", ExecutedQty: "0"},
+		{Symbol: "BBBBTC", Side: "BUY", Price: "0.1", OrigQty: "10", ExecutedQty: "0"},
+	}
+	got := openBuyNotional(orders)
+	if len(got) != 1 || got["AAA"] != 17 {
+		t.Errorf("openBuyNotional = %v, want map[AAA:17]", got)
+	}
+}
+
+func TestEvaluateBuyRisk(t *testing.T) {
+	config := RiskConfig{MinBalance: 10, RiskPercentage: 2, PositionSize: 50, MaxTotalExposure: 50}
+	portfolio := &Portfolio{
+		Entries: []PortfolioEntry{
+			{Asset: "USDT", Free: 600, Value: 600},
+			{Asset: "AAA", Value: 20},
+			{Asset: "BBB", Value: 380},
+		},
+		TotalEquity: 1000,
+	}
+
+	tests := []struct {
+		name     string
+		openBuys map[string]float64
+		asset    string
+		notional float64
+		stop     float64
+		wantRule string
+	}{
+		{name: "within every limit", asset: "AAA", notional: 20, wantRule: ""},
+		{name: "reserve", asset: "CCC", notional: 595, wantRule: "MIN_BALANCE"},
+		{name: "held coin", asset: "AAA", notional: 31, wantRule: "POSITION_SIZE"},
+		{name: "open buys count toward the coin", openBuys: map[string]float64{"AAA": 25}, asset: "AAA", notional: 10, wantRule: "POSITION_SIZE"},
+		{name: "open buys count toward the total", openBuys: map[string]float64{"DDD": 90}, asset: "CCC", notional: 20, wantRule: "MAX_TOTAL_EXPOSURE"},
+		{name: "loss at the stop within the budget", asset: "AAA", notional: 20, stop: 0.5, wantRule: ""},
+		{name: "loss at the stop over the budget", asset: "AAA", notional: 20, stop: 0.4, wantRule: "RISK_PERCENTAGE"},
+		{name: "stop above the price", asset: "AAA", notional: 20, stop: 1.5, wantRule: "STOP_LOSS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evaluateBuyRisk(config, portfolio, tt.openBuys, tt.asset, tt.notional, 1, tt.stop)
+			rule := ""
+			if riskErr, ok := err.(*RiskError); ok {
+				rule = riskErr.Rule
+			} else if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if rule != tt.wantRule {
+				t.Errorf("rule = %q (%v), want %q", rule, err, tt.wantRule)
+			}
+		})
+	}
+}
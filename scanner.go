@@ -16,9 +16,29 @@ func getBaseCoin(symbol string) string {
 	return symbol
 }
 
-// Scan for best coins based on new listings (≤30 days)
+// newCoinCriteria returns the scan criteria for NEW coins; the age window and recovery filter come from .env
+func newCoinCriteria() ScanCriteria {
+	return ScanCriteria{
+		MinVolume:       50000,                              // 50K+ USDT volume (lower for newer coins)
+		MaxPrice:        2.0,                                // Maximum $2 (slightly higher for more options)
+		MinPrice:        0.000001,                           // Minimum price
+		MinPriceChange:  -90.0,                              // Allow deep dips (new coins volatile)
+		MaxPriceChange:  1000.0,                             // Allow massive gains for new coins
+		MinAge:          envInt("MIN_AGE_DAYS", 1),          // 1+ day minimum
+		MaxAge:          envInt("MAX_AGE_DAYS", 30),         // listed within 30 days
+		RequireRecovery: envBool("REQUIRE_RECOVERY", false), // Off by default: new coins don't need recovery history
+		Recovery:        loadRecoveryConfig(),
+		MinScore:        20.0, // Lower threshold for more results
+		MaxResults:      25,   // Show top 25 coins
+	}
+}
+
+// Scan for best coins based on new listings (≤MaxAge days)
 func scanBestCoins() ([]CoinInfo, error) {
-	fmt.Println("🔍 กำลังค้นหาเหรียญใหม่ (≤30 วัน) ด้วยกระบวนการ 2 ขั้นตอน...")
+	// Define scan criteria for NEW coins
+	criteria := newCoinCriteria()
+
+	fmt.Printf("🔍 กำลังค้นหาเหรียญใหม่ (%d-%d วัน) ด้วยกระบวนการ 2 ขั้นตอน...\n", criteria.MinAge, criteria.MaxAge)
 	fmt.Println("📅 ขั้นตอน 1: ใช้ timeframe 3 เดือน (4 เดือนย้อนหลัง) กรองเหรียญใหม่")
 	fmt.Println("📊 ขั้นตอน 2: ใช้ timeframe 1 วัน (144 วันย้อนหลัง) วิเคราะห์เหรียญที่ผ่านการกรอง")
 
@@ -62,23 +82,16 @@ func scanBestCoins() ([]CoinInfo, error) {
 		return []CoinInfo{}, nil
 	}
 
+	model, err := loadScoringModel()
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลดโมเดลคะแนน: %v", err)
+	}
+	fmt.Printf("🧮 โมเดลคะแนน: %s - %s\n", model.label(), model.Description)
+
 	// STEP 2: Analyze filtered coins with daily timeframe (144 days back)
 	fmt.Println("🔍 STEP 2: วิเคราะห์เหรียญใหม่ด้วย timeframe 1 วัน (144 วันย้อนหลัง)...")
 
-	// Define scan criteria for NEW coins
-	criteria := ScanCriteria{
-		MinVolume:       50000,    // 50K+ USDT volume (lower for newer coins)
-		MaxPrice:        2.0,      // Maximum $2 (slightly higher for more options)
-		MinPrice:        0.000001, // Minimum price
-		MinPriceChange:  -90.0,    // Allow deep dips (new coins volatile)
-		MaxPriceChange:  1000.0,   // Allow massive gains for new coins
-		MinAge:          1,        // 1+ day minimum
-		RequireRecovery: false,    // New coins don't need recovery history
-		MinScore:        20.0,     // Lower threshold for more results
-		MaxResults:      25,       // Show top 25 coins
-	}
-
-	var candidates []CoinInfo
+	var filtered []scanCandidate
 
 	for i, ticker := range newCoinTickers {
 		if i%5 == 0 {
@@ -86,8 +99,21 @@ func scanBestCoins() ([]CoinInfo, error) {
 		}
 
 		// Use daily data (144 days) for detailed analysis
-		coinInfo := processNewCoinTicker(ticker, criteria)
-		if coinInfo != nil {
+		if candidate := processNewCoinTicker(ticker, criteria); candidate != nil {
+			filtered = append(filtered, *candidate)
+		}
+	}
+
+	// Normalized factors are ranked against every coin that passed the filters
+	all := make([]ScoreInputs, len(filtered))
+	for i, candidate := range filtered {
+		all[i] = candidate.inputs
+	}
+	universe := newScoreUniverse(model, all)
+
+	var candidates []CoinInfo
+	for _, candidate := range filtered {
+		if coinInfo := scoreCandidate(candidate, criteria, model, universe); coinInfo != nil {
 			candidates = append(candidates, *coinInfo)
 		}
 	}
@@ -123,8 +149,44 @@ func analyzeCoinsForAccumulation(coins []CoinInfo) ([]AINewCoinAnalysis, error)
 	return analyses, nil // Return all analyses
 }
 
+// analyzeSymbol runs the AI analysis for a single symbol that may not be in the scan results
+func analyzeSymbol(client *BinanceClient, symbol string) (*AINewCoinAnalysis, error) {
+	price, err := getCurrentPriceForSymbol(client, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึงราคา %s: %v", symbol, err)
+	}
+
+	coin := CoinInfo{
+		Symbol:      symbol,
+		BaseCoin:    getBaseCoin(symbol),
+		Price:       price,
+		AgeDays:     getCoinAgeDaysDetailed(symbol),
+		LastUpdated: time.Now(),
+	}
+
+	analyses, err := analyzeNewCoinsWithAI([]CoinInfo{coin})
+	if err != nil {
+		return nil, err
+	}
+	if len(analyses) == 0 {
+		return nil, fmt.Errorf("ข้อมูล %s ไม่เพียงพอสำหรับวิเคราะห์", symbol)
+	}
+	return &analyses[0], nil
+}
+
+// scanCandidate represents a ticker that passed the filters and waits to be scored
+type scanCandidate struct {
+	ticker      Ticker24hr
+	price       float64
+	volume      float64
+	priceChange float64
+	inputs      ScoreInputs
+	ageDays     int
+	recovery    *RecoveryProfile // only measured when RequireRecovery is on
+}
+
 // Process new coin ticker with detailed analysis
-func processNewCoinTicker(ticker Ticker24hr, criteria ScanCriteria) *CoinInfo {
+func processNewCoinTicker(ticker Ticker24hr, criteria ScanCriteria) *scanCandidate {
 	// Parse numeric values
 	price, err := strconv.ParseFloat(ticker.LastPrice, 64)
 	if err != nil || price < criteria.MinPrice || price > criteria.MaxPrice {
@@ -141,23 +203,57 @@ func processNewCoinTicker(ticker Ticker24hr, criteria ScanCriteria) *CoinInfo {
 		return nil
 	}
 
+	// Accurate listing age, needed for the age window and the age factor
+	ageDays := getCoinAgeDaysDetailed(ticker.Symbol)
+	if ageDays < criteria.MinAge || (criteria.MaxAge > 0 && ageDays > criteria.MaxAge) {
+		return nil
+	}
+
+	// Keep only coins in an accepted post-listing phase
+	var recovery *RecoveryProfile
+	if criteria.RequireRecovery {
+		recovery, err = analyzeRecovery(ticker.Symbol, ageDays, criteria.Recovery)
+		if err != nil || !criteria.Recovery.accepts(recovery.Phase) {
+			return nil
+		}
+	}
+
+	inputs := newScoreInputs(ticker, price, volume, priceChange)
+	inputs.AgeDays = float64(ageDays)
+
+	return &scanCandidate{
+		ticker:      ticker,
+		price:       price,
+		volume:      volume,
+		priceChange: priceChange,
+		inputs:      inputs,
+		ageDays:     ageDays,
+		recovery:    recovery,
+	}
+}
+
+// scoreCandidate scores a filtered ticker and drops it below criteria.MinScore
+func scoreCandidate(c scanCandidate, criteria ScanCriteria, model *ScoringModel, universe *ScoreUniverse) *CoinInfo {
 	// Calculate score focused on NEW coin potential
-	score := calculateNewCoinScore(ticker, price, volume, priceChange)
+	score, breakdown := model.Score(c.inputs, universe)
 	if score < criteria.MinScore {
 		return nil
 	}
 
-	baseCoin := getBaseCoin(ticker.Symbol)
+	baseCoin := getBaseCoin(c.ticker.Symbol)
 
 	return &CoinInfo{
-		Symbol:      ticker.Symbol,
+		Symbol:      c.ticker.Symbol,
 		BaseCoin:    baseCoin,
-		Price:       price,
-		Volume24h:   volume,
-		PriceChange: priceChange,
+		Price:       c.price,
+		Volume24h:   c.volume,
+		PriceChange: c.priceChange,
 		Score:       score,
-		Reason:      generateNewCoinReason(ticker, price, volume, priceChange, score),
-		AgeDays:     getCoinAgeDaysDetailed(ticker.Symbol),
+		ScoreModel:  model.label(),
+		Breakdown:   breakdown,
+		Reason:      generateNewCoinReason(c.ticker, c.price, c.volume, c.priceChange, score),
+		AgeDays:     c.ageDays,
+		Recovery:    c.recovery,
 		LastUpdated: time.Now(),
 	}
 }
@@ -187,80 +283,6 @@ func isExcludedSymbol(symbol string) bool {
 	return false
 }
 
-// Calculate score focused on NEW coin potential
-func calculateNewCoinScore(ticker Ticker24hr, price, volume, priceChange float64) float64 {
-	score := 0.0
-
-	// Volume score (40% weight) - critical for new coins
-	volumeScore := 0.0
-	if volume >= 1000000 { // 1M+ exceptional for new coin
-		volumeScore = 40.0
-	} else if volume >= 500000 { // 500K+ very good
-		volumeScore = 35.0
-	} else if volume >= 200000 { // 200K+ good
-		volumeScore = 30.0
-	} else if volume >= 100000 { // 100K+ acceptable
-		volumeScore = 25.0
-	} else if volume >= 50000 { // 50K+ minimum
-		volumeScore = 15.0
-	}
-	score += volumeScore
-
-	// New coin price potential (30% weight) - very low price = high potential
-	priceScore := 0.0
-	if price <= 0.000001 {
-		priceScore = 30.0 // Ultra micro cap new coin
-	} else if price <= 0.00001 {
-		priceScore = 28.0
-	} else if price <= 0.0001 {
-		priceScore = 25.0
-	} else if price <= 0.001 {
-		priceScore = 22.0
-	} else if price <= 0.01 {
-		priceScore = 18.0
-	} else if price <= 0.1 {
-		priceScore = 15.0
-	} else if price <= 1.0 {
-		priceScore = 10.0
-	} else if price <= 2.0 {
-		priceScore = 5.0
-	}
-	score += priceScore
-
-	// New coin momentum (20% weight) - can be volatile
-	momentumScore := 0.0
-	if priceChange >= 50.0 { // Strong rally
-		momentumScore = 20.0
-	} else if priceChange >= 20.0 { // Good momentum
-		momentumScore = 18.0
-	} else if priceChange >= 0.0 { // Positive
-		momentumScore = 15.0
-	} else if priceChange >= -20.0 { // Small dip - good entry
-		momentumScore = 18.0
-	} else if priceChange >= -50.0 { // Bigger dip - opportunity
-		momentumScore = 22.0
-	} else {
-		// Very deep dip - high risk/reward
-		momentumScore = 15.0
-	}
-	score += momentumScore
-
-	// Activity bonus (10% weight) - trade count important for new coins
-	activityScore := 0.0
-	if ticker.Count >= 50000 {
-		activityScore = 10.0
-	} else if ticker.Count >= 10000 {
-		activityScore = 8.0
-	} else if ticker.Count >= 5000 {
-		activityScore = 6.0
-	} else if ticker.Count >= 1000 {
-		activityScore = 4.0
-	}
-	score += activityScore
-
-	return score
-}
-
 // Generate reason for NEW coin selection (in Thai)
 func generateNewCoinReason(ticker Ticker24hr, price, volume, priceChange, score float64) string {
 	reasons := []string{}
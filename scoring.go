@@ -0,0 +1,523 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Highest scoring file format this build understands
+	scoringConfigVersion = 1
+	defaultScoringFile   = "scoring.json"
+	legacyScoringModel   = "legacy"
+)
+
+// ScoreTier represents one breakpoint: the first tier whose bounds contain the input wins
+type ScoreTier struct {
+	Min   *float64 `json:"min,omitempty"` // inclusive lower bound, nil = no bound
+	Max   *float64 `json:"max,omitempty"` // inclusive upper bound, nil = no bound
+	Value float64  `json:"value"`
+}
+
+// ScoreCurve represents a continuous mapping of the input onto 0-1 between From and To;
+// From above To rewards low inputs. "log" interpolates on log10 of the input, "decay"
+// is 1 up to From and then halves every HalfLife
+type ScoreCurve struct {
+	Type     string  `json:"type"` // linear, log or decay
+	From     float64 `json:"from"`
+	To       float64 `json:"to,omitempty"`
+	HalfLife float64 `json:"halfLife,omitempty"`
+}
+
+// ScoreFactor represents one input of a scoring model; points = value × weight
+type ScoreFactor struct {
+	Input     string      `json:"input"`               // volume, price, change, trades, liquidity, age, spread or volatility
+	Normalize string      `json:"normalize,omitempty"` // percentile or zscore within the scan, empty = absolute
+	Weight    float64     `json:"weight"`
+	Tiers     []ScoreTier `json:"tiers,omitempty"`
+	Curve     *ScoreCurve `json:"curve,omitempty"`
+}
+
+// ScoringModel represents a named set of factors. A model that extends another starts from
+// its factors; its own factors replace the base's factor for the same input or are added
+type ScoringModel struct {
+	Name        string        `json:"-"`
+	Version     int           `json:"version"`
+	Description string        `json:"description"`
+	Extends     string        `json:"extends,omitempty"`
+	Factors     []ScoreFactor `json:"factors"`
+}
+
+// ScoringConfig represents the scoring file: several models side by side
+type ScoringConfig struct {
+	Version int                      `json:"version"`
+	Default string                   `json:"default"`
+	Models  map[string]*ScoringModel `json:"models"`
+}
+
+// FactorScore represents one factor's share of a score, so a ranking can be audited
+type FactorScore struct {
+	Input      string   `json:"input"`
+	Raw        float64  `json:"raw"`                  // input value before scoring
+	Normalized *float64 `json:"normalized,omitempty"` // percentile (0-1) or z-score fed to the tiers/curve
+	Tier       int      `json:"tier,omitempty"`       // 1-based matching tier, 0 for curves or no match
+	Value      float64  `json:"value"`                // tier value or curve output (0-1)
+	Weight     float64  `json:"weight"`
+	Points     float64  `json:"points"`            // value × weight
+	Unknown    bool     `json:"unknown,omitempty"` // the input was unavailable and scored 0
+}
+
+// ScoreInputs represents the market data a model can score
+type ScoreInputs struct {
+	Volume     float64 // 24h quote volume in USDT
+	Price      float64
+	Change     float64 // 24h price change percent
+	Trades     float64 // 24h trade count
+	Liquidity  float64 // USDT resting at the best bid and ask
+	AgeDays    float64
+	Spread     float64 // bid/ask spread in percent of mid
+	Volatility float64 // 24h high-low range in percent of low
+
+	// Inputs the ticker could not provide; a missing spread of 0 must not look like a perfect one
+	Unknown map[string]bool
+}
+
+// scoreInputNames are the inputs a factor may reference
+var scoreInputNames = []string{"volume", "price", "change", "trades", "liquidity", "age", "spread", "volatility"}
+
+// value returns the named input
+func (in ScoreInputs) value(name string) (float64, bool) {
+	switch name {
+	case "volume":
+		return in.Volume, true
+	case "price":
+		return in.Price, true
+	case "change":
+		return in.Change, true
+	case "trades":
+		return in.Trades, true
+	case "liquidity":
+		return in.Liquidity, true
+	case "age":
+		return in.AgeDays, true
+	case "spread":
+		return in.Spread, true
+	case "volatility":
+		return in.Volatility, true
+	}
+	return 0, false
+}
+
+// newScoreInputs extracts the ticker based inputs; AgeDays is filled in by the caller
+func newScoreInputs(ticker Ticker24hr, price, volume, priceChange float64) ScoreInputs {
+	inputs := ScoreInputs{
+		Volume: volume,
+		Price:  price,
+		Change: priceChange,
+		Trades: float64(ticker.Count),
+	}
+
+	unknown := func(name string) {
+		if inputs.Unknown == nil {
+			inputs.Unknown = make(map[string]bool)
+		}
+		inputs.Unknown[name] = true
+	}
+
+	bid, _ := strconv.ParseFloat(ticker.BidPrice, 64)
+	ask, _ := strconv.ParseFloat(ticker.AskPrice, 64)
+	if bid > 0 && ask >= bid {
+		inputs.Spread = (ask - bid) / ((ask + bid) / 2) * 100
+	} else {
+		// Missing or crossed book
+		unknown("spread")
+	}
+	bidQty, _ := strconv.ParseFloat(ticker.BidQty, 64)
+	askQty, _ := strconv.ParseFloat(ticker.AskQty, 64)
+	if bid > 0 && ask > 0 {
+		inputs.Liquidity = bid*bidQty + ask*askQty
+	} else {
+		unknown("liquidity")
+	}
+
+	high, _ := strconv.ParseFloat(ticker.HighPrice, 64)
+	low, _ := strconv.ParseFloat(ticker.LowPrice, 64)
+	if low > 0 && high >= low {
+		inputs.Volatility = (high - low) / low * 100
+	} else {
+		unknown("volatility")
+	}
+	return inputs
+}
+
+// bound is a small helper for writing tiers in Go
+func bound(v float64) *float64 {
+	return &v
+}
+
+// legacyModel reproduces the original hard-coded tiers and is used when no scoring file exists
+func legacyModel() *ScoringModel {
+	return &ScoringModel{
+		Name:        legacyScoringModel,
+		Version:     1,
+		Description: "Original tiers: volume 40, price 30, momentum 20, activity 10",
+		Factors: []ScoreFactor{
+			{Input: "volume", Weight: 1, Tiers: []ScoreTier{
+				{Min: bound(1000000), Value: 40},
+				{Min: bound(500000), Value: 35},
+				{Min: bound(200000), Value: 30},
+				{Min: bound(100000), Value: 25},
+				{Min: bound(50000), Value: 15},
+			}},
+			{Input: "price", Weight: 1, Tiers: []ScoreTier{
+				{Max: bound(0.000001), Value: 30},
+				{Max: bound(0.00001), Value: 28},
+				{Max: bound(0.0001), Value: 25},
+				{Max: bound(0.001), Value: 22},
+				{Max: bound(0.01), Value: 18},
+				{Max: bound(0.1), Value: 15},
+				{Max: bound(1.0), Value: 10},
+				{Max: bound(2.0), Value: 5},
+			}},
+			{Input: "change", Weight: 1, Tiers: []ScoreTier{
+				{Min: bound(50), Value: 20},
+				{Min: bound(20), Value: 18},
+				{Min: bound(0), Value: 15},
+				{Min: bound(-20), Value: 18},
+				{Min: bound(-50), Value: 22},
+				{Value: 15},
+			}},
+			{Input: "trades", Weight: 1, Tiers: []ScoreTier{
+				{Min: bound(50000), Value: 10},
+				{Min: bound(10000), Value: 8},
+				{Min: bound(5000), Value: 6},
+				{Min: bound(1000), Value: 4},
+			}},
+		},
+	}
+}
+
+// validate checks a model after loading
+func (m *ScoringModel) validate() error {
+	if len(m.Factors) == 0 {
+		return fmt.Errorf("โมเดล %s ไม่มี factor", m.Name)
+	}
+	for i, factor := range m.Factors {
+		if _, ok := (ScoreInputs{}).value(factor.Input); !ok {
+			return fmt.Errorf("โมเดล %s factor %d: ไม่รู้จัก input %q (%s)", m.Name, i+1, factor.Input, strings.Join(scoreInputNames, ", "))
+		}
+		if factor.Normalize != "" && factor.Normalize != "percentile" && factor.Normalize != "zscore" {
+			return fmt.Errorf("โมเดล %s factor %s: normalize %q ต้องเป็น percentile หรือ zscore", m.Name, factor.Input, factor.Normalize)
+		}
+		if (len(factor.Tiers) == 0) == (factor.Curve == nil) {
+			return fmt.Errorf("โมเดล %s factor %s: ต้องมี tiers หรือ curve อย่างใดอย่างหนึ่ง", m.Name, factor.Input)
+		}
+		if curve := factor.Curve; curve != nil {
+			if curve.Type != "linear" && curve.Type != "log" && curve.Type != "decay" {
+				return fmt.Errorf("โมเดล %s factor %s: curve type %q ต้องเป็น linear, log หรือ decay", m.Name, factor.Input, curve.Type)
+			}
+			if curve.Type == "decay" {
+				if curve.HalfLife <= 0 {
+					return fmt.Errorf("โมเดล %s factor %s: decay curve ต้องมี halfLife มากกว่า 0", m.Name, factor.Input)
+				}
+				continue
+			}
+			if curve.From == curve.To {
+				return fmt.Errorf("โมเดล %s factor %s: curve from และ to ต้องไม่เท่ากัน", m.Name, factor.Input)
+			}
+			if curve.Type == "log" && (curve.From <= 0 || curve.To <= 0) {
+				return fmt.Errorf("โมเดล %s factor %s: log curve ต้องมี from และ to มากกว่า 0", m.Name, factor.Input)
+			}
+		}
+	}
+	return nil
+}
+
+// uses reports whether any factor reads the named input
+func (m *ScoringModel) uses(input string) bool {
+	for _, factor := range m.Factors {
+		if factor.Input == input {
+			return true
+		}
+	}
+	return false
+}
+
+// label identifies the model and version recorded on each CoinInfo
+func (m *ScoringModel) label() string {
+	return fmt.Sprintf("%s@%d", m.Name, m.Version)
+}
+
+// evaluate maps an input onto the factor's value (before weighting) and the 1-based tier it matched
+func (f ScoreFactor) evaluate(x float64) (float64, int) {
+	if f.Curve == nil {
+		for i, tier := range f.Tiers {
+			if (tier.Min == nil || x >= *tier.Min) && (tier.Max == nil || x <= *tier.Max) {
+				return tier.Value, i + 1
+			}
+		}
+		return 0, 0
+	}
+
+	from, to := f.Curve.From, f.Curve.To
+	if f.Curve.Type == "decay" {
+		if x <= from {
+			return 1, 0
+		}
+		return math.Pow(0.5, (x-from)/f.Curve.HalfLife), 0
+	}
+	if f.Curve.Type == "log" {
+		if x <= 0 {
+			x = math.Min(from, to)
+		}
+		x, from, to = math.Log10(x), math.Log10(from), math.Log10(to)
+	}
+	return math.Max(0, math.Min(1, (x-from)/(to-from))), 0
+}
+
+// ScoreUniverse holds every scanned coin's inputs so factors can be scored relative to the scan
+type ScoreUniverse struct {
+	sorted map[string][]float64
+	mean   map[string]float64
+	stdDev map[string]float64
+}
+
+// newScoreUniverse collects the inputs the model normalizes across all candidates of one scan
+func newScoreUniverse(model *ScoringModel, all []ScoreInputs) *ScoreUniverse {
+	universe := &ScoreUniverse{
+		sorted: make(map[string][]float64),
+		mean:   make(map[string]float64),
+		stdDev: make(map[string]float64),
+	}
+	for _, factor := range model.Factors {
+		if factor.Normalize == "" {
+			continue
+		}
+		if _, done := universe.sorted[factor.Input]; done {
+			continue
+		}
+
+		// Unknown inputs are left out so they do not drag the ranks of the known ones
+		values := make([]float64, 0, len(all))
+		for _, inputs := range all {
+			if !inputs.Unknown[factor.Input] {
+				value, _ := inputs.value(factor.Input)
+				values = append(values, value)
+			}
+		}
+		sort.Float64s(values)
+		universe.sorted[factor.Input] = values
+
+		if len(values) == 0 {
+			continue
+		}
+		mean := calculateAverage(values)
+		variance := 0.0
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		universe.mean[factor.Input] = mean
+		universe.stdDev[factor.Input] = math.Sqrt(variance / float64(len(values)))
+	}
+	return universe
+}
+
+// percentile returns the mid-rank of x within the scan (0-1), 0.5 when there is nothing to compare with
+func (u *ScoreUniverse) percentile(input string, x float64) float64 {
+	values := u.sorted[input]
+	if len(values) == 0 {
+		return 0.5
+	}
+	below := sort.SearchFloat64s(values, x)
+	equal := sort.Search(len(values), func(i int) bool { return values[i] > x }) - below
+	return (float64(below) + float64(equal)/2) / float64(len(values))
+}
+
+// zScore returns how many standard deviations x is from the scan mean
+func (u *ScoreUniverse) zScore(input string, x float64) float64 {
+	if u.stdDev[input] == 0 {
+		return 0
+	}
+	return (x - u.mean[input]) / u.stdDev[input]
+}
+
+// Score adds up every factor's weighted points and returns the per-factor breakdown;
+// normalized factors are ranked within universe (nil scores them as average) and unknown inputs score 0
+func (m *ScoringModel) Score(inputs ScoreInputs, universe *ScoreUniverse) (float64, []FactorScore) {
+	if universe == nil {
+		universe = &ScoreUniverse{}
+	}
+
+	score := 0.0
+	breakdown := make([]FactorScore, 0, len(m.Factors))
+	for _, factor := range m.Factors {
+		raw, _ := inputs.value(factor.Input)
+		result := FactorScore{Input: factor.Input, Raw: raw, Weight: factor.Weight}
+		if inputs.Unknown[factor.Input] {
+			result.Unknown = true
+			breakdown = append(breakdown, result)
+			continue
+		}
+
+		x := raw
+		switch factor.Normalize {
+		case "percentile":
+			x = universe.percentile(factor.Input, raw)
+			result.Normalized = &x
+		case "zscore":
+			x = universe.zScore(factor.Input, raw)
+			result.Normalized = &x
+		}
+
+		result.Value, result.Tier = factor.evaluate(x)
+		result.Points = result.Value * factor.Weight
+		score += result.Points
+		breakdown = append(breakdown, result)
+	}
+	return score, breakdown
+}
+
+// formatScoreBreakdown renders a breakdown on one line, e.g. "volume 30.0 (300000, tier 3) + ..."
+func formatScoreBreakdown(breakdown []FactorScore) string {
+	parts := make([]string, 0, len(breakdown))
+	for _, factor := range breakdown {
+		if factor.Unknown {
+			parts = append(parts, fmt.Sprintf("%s 0.0 (ไม่มีข้อมูล)", factor.Input))
+			continue
+		}
+		detail := strconv.FormatFloat(factor.Raw, 'g', 6, 64)
+		if factor.Normalized != nil {
+			detail += fmt.Sprintf(" → %.2f", *factor.Normalized)
+		}
+		if factor.Tier > 0 {
+			detail += fmt.Sprintf(", tier %d", factor.Tier)
+		} else if factor.Weight != 1 {
+			detail += fmt.Sprintf(", %.2f×%g", factor.Value, factor.Weight)
+		}
+		parts = append(parts, fmt.Sprintf("%s %.1f (%s)", factor.Input, factor.Points, detail))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// loadScoringConfig reads the scoring file; a missing file yields only the legacy model
+func loadScoringConfig(path string) (*ScoringConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ScoringConfig{
+			Version: scoringConfigVersion,
+			Default: legacyScoringModel,
+			Models:  map[string]*ScoringModel{legacyScoringModel: legacyModel()},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config ScoringConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if config.Version < 1 || config.Version > scoringConfigVersion {
+		return nil, fmt.Errorf("%s: ไม่รองรับ version %d (รองรับถึง %d)", path, config.Version, scoringConfigVersion)
+	}
+	if config.Models == nil {
+		config.Models = make(map[string]*ScoringModel)
+	}
+	if _, exists := config.Models[legacyScoringModel]; !exists {
+		config.Models[legacyScoringModel] = legacyModel()
+	}
+
+	for name, model := range config.Models {
+		model.Name = name
+	}
+	resolved := make(map[string]bool)
+	for _, name := range config.modelNames() {
+		if err := config.resolve(name, resolved, nil); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if err := config.Models[name].validate(); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return &config, nil
+}
+
+// resolve merges a model's factors onto the model it extends, bases first; chain guards against cycles
+func (c *ScoringConfig) resolve(name string, resolved map[string]bool, chain []string) error {
+	model := c.Models[name]
+	if resolved[name] || model.Extends == "" {
+		resolved[name] = true
+		return nil
+	}
+	for _, seen := range chain {
+		if seen == name {
+			return fmt.Errorf("โมเดล %s: extends วนซ้ำ (%s)", name, strings.Join(append(chain, name), " → "))
+		}
+	}
+	base, ok := c.Models[model.Extends]
+	if !ok {
+		return fmt.Errorf("โมเดล %s: ไม่พบโมเดลฐาน %q", name, model.Extends)
+	}
+	if err := c.resolve(model.Extends, resolved, append(chain, name)); err != nil {
+		return err
+	}
+
+	factors := append([]ScoreFactor(nil), base.Factors...)
+	for _, own := range model.Factors {
+		replaced := false
+		for i := range factors {
+			if factors[i].Input == own.Input {
+				factors[i], replaced = own, true
+				break
+			}
+		}
+		if !replaced {
+			factors = append(factors, own)
+		}
+	}
+	model.Factors = factors
+	resolved[name] = true
+	return nil
+}
+
+// modelNames returns the configured model names in order
+func (c *ScoringConfig) modelNames() []string {
+	names := make([]string, 0, len(c.Models))
+	for name := range c.Models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadScoringModel picks SCORING_MODEL (or the file's default) from SCORING_FILE
+func loadScoringModel() (*ScoringModel, error) {
+	path := os.Getenv("SCORING_FILE")
+	if path == "" {
+		path = defaultScoringFile
+	}
+	config, err := loadScoringConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := os.Getenv("SCORING_MODEL")
+	if name == "" {
+		name = config.Default
+	}
+	if name == "" {
+		name = legacyScoringModel
+	}
+
+	model, ok := config.Models[name]
+	if !ok {
+		return nil, fmt.Errorf("ไม่พบโมเดลคะแนน %q ใน %s (มี: %s)", name, path, strings.Join(config.modelNames(), ", "))
+	}
+	return model, nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScoringModelScore(t *testing.T) {
+	model := &ScoringModel{Name: "test", Version: 1, Factors: []ScoreFactor{
+		{Input: "volume", Weight: 2, Tiers: []ScoreTier{{Min: bound(100), Value: 10}, {Min: bound(0), Value: 5}}},
+		{Input: "spread", Weight: 10, Curve: &ScoreCurve{Type: "linear", From: 1, To: 0}},
+		{Input: "age", Weight: 20, Curve: &ScoreCurve{Type: "decay", From: 3, HalfLife: 5}},
+	}}
+
+	tests := []struct {
+		name        string
+		inputs      ScoreInputs
+		wantScore   float64
+		wantTier    int
+		wantUnknown bool
+	}{
+		{
+			name:      "tiers, reversed linear curve and decay",
+			inputs:    ScoreInputs{Volume: 150, Spread: 0.25, AgeDays: 8},
+			wantScore: 10*2 + 0.75*10 + 0.5*20,
+			wantTier:  1,
+		},
+		{
+			name:      "second tier and fresh listing",
+			inputs:    ScoreInputs{Volume: 50, Spread: 2, AgeDays: 1},
+			wantScore: 5*2 + 0 + 20,
+			wantTier:  2,
+		},
+		{
+			name:        "unknown spread scores nothing",
+			inputs:      ScoreInputs{Volume: 150, AgeDays: 8, Unknown: map[string]bool{"spread": true}},
+			wantScore:   10*2 + 0.5*20,
+			wantTier:    1,
+			wantUnknown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, breakdown := model.Score(tt.inputs, nil)
+			if math.Abs(score-tt.wantScore) > 1e-9 {
+				t.Errorf("score = %v, want %v", score, tt.wantScore)
+			}
+			if len(breakdown) != len(model.Factors) {
+				t.Fatalf("breakdown has %d factors, want %d", len(breakdown), len(model.Factors))
+			}
+			total := 0.0
+			for _, factor := range breakdown {
+				total += factor.Points
+			}
+			if math.Abs(total-score) > 1e-9 {
+				t.Errorf("breakdown adds up to %v, score is %v", total, score)
+			}
+			if breakdown[0].Tier != tt.wantTier {
+				t.Errorf("volume tier = %d, want %d", breakdown[0].Tier, tt.wantTier)
+			}
+			if breakdown[1].Unknown != tt.wantUnknown {
+				t.Errorf("spread unknown = %v, want %v", breakdown[1].Unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestScoreUniversePercentile(t *testing.T) {
+	model := &ScoringModel{Factors: []ScoreFactor{{Input: "volume", Normalize: "percentile"}}}
+	all := []ScoreInputs{{Volume: 3}, {Volume: 1}, {Volume: 2}, {Volume: 2}, {Volume: 9, Unknown: map[string]bool{"volume": true}}}
+	universe := newScoreUniverse(model, all)
+
+	tests := []struct {
+		name string
+		x    float64
+		want float64
+	}{
+		{"below every value", 0, 0},
+		{"lowest value counts half of itself", 1, 0.125},
+		{"ties share the mid rank", 2, 0.5},
+		{"highest value", 3, 0.875},
+		{"above every value", 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := universe.percentile("volume", tt.x); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("percentile(%v) = %v, want %v", tt.x, got, tt.want)
+			}
+		})
+	}
+
+	if got := universe.percentile("trades", 1); got != 0.5 {
+		t.Errorf("percentile of an input without a universe = %v, want 0.5", got)
+	}
+}
+
+func TestNewScoreInputsUnknown(t *testing.T) {
+	tests := []struct {
+		name        string
+		ticker      Ticker24hr
+		wantUnknown []string
+		wantSpread  float64
+	}{
+		{
+			name:       "full book",
+			ticker:     Ticker24hr{BidPrice: "0.99", AskPrice: "1.01", BidQty: "1", AskQty: "1", HighPrice: "1.1", LowPrice: "1"},
+			wantSpread: 2,
+		},
+		{
+			name:        "missing book",
+			ticker:      Ticker24hr{HighPrice: "1.1", LowPrice: "1"},
+			wantUnknown: []string{"spread", "liquidity"},
+		},
+		{
+			name:        "crossed book",
+			ticker:      Ticker24hr{BidPrice: "1.01", AskPrice: "0.99", HighPrice: "1.1", LowPrice: "1"},
+			wantUnknown: []string{"spread"},
+		},
+		{
+			name:        "no 24h range",
+			ticker:      Ticker24hr{BidPrice: "0.99", AskPrice: "1.01"},
+			wantUnknown: []string{"volatility"},
+			wantSpread:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := newScoreInputs(tt.ticker, 1, 0, 0)
+			if len(inputs.Unknown) != len(tt.wantUnknown) {
+				t.Errorf("unknown = %v, want %v", inputs.Unknown, tt.wantUnknown)
+			}
+			for _, name := range tt.wantUnknown {
+				if !inputs.Unknown[name] {
+					t.Errorf("%s not marked unknown", name)
+				}
+			}
+			if math.Abs(inputs.Spread-tt.wantSpread) > 1e-9 {
+				t.Errorf("spread = %v, want %v", inputs.Spread, tt.wantSpread)
+			}
+		})
+	}
+}
+
+func TestLoadScoringConfigExtends(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "scoring.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("extension replaces and adds factors", func(t *testing.T) {
+		path := write(t, `{"version": 1, "models": {"aged": {"version": 1, "extends": "legacy", "factors": [
+			{"input": "trades", "weight": 2, "tiers": [{"value": 1}]},
+			{"input": "age", "weight": 20, "curve": {"type": "decay", "from": 3, "halfLife": 5}}
+		]}}}`)
+		config, err := loadScoringConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		aged := config.Models["aged"]
+		legacy := legacyModel()
+		if len(aged.Factors) != len(legacy.Factors)+1 {
+			t.Fatalf("aged has %d factors, want %d", len(aged.Factors), len(legacy.Factors)+1)
+		}
+		score, _ := aged.Score(ScoreInputs{Volume: 1000000, Price: 0.0000005, Change: 60, AgeDays: 1}, nil)
+		if want := 40.0 + 30 + 20 + 2 + 20; score != want {
+			t.Errorf("score = %v, want %v", score, want)
+		}
+		if len(config.Models[legacyScoringModel].Factors) != len(legacy.Factors) {
+			t.Errorf("extending changed the base model")
+		}
+	})
+
+	t.Run("missing base", func(t *testing.T) {
+		path := write(t, `{"version": 1, "models": {"a": {"version": 1, "extends": "nope", "factors": []}}}`)
+		if _, err := loadScoringConfig(path); err == nil {
+			t.Error("expected an error for a missing base")
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		path := write(t, `{"version": 1, "models": {
+			"a": {"version": 1, "extends": "b", "factors": []},
+			"b": {"version": 1, "extends": "a", "factors": []}}}`)
+		if _, err := loadScoringConfig(path); err == nil {
+			t.Error("expected an error for an extends cycle")
+		}
+	})
+
+	t.Run("shipped file", func(t *testing.T) {
+		config, err := loadScoringConfig(defaultScoringFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range config.modelNames() {
+			if len(config.Models[name].Factors) == 0 {
+				t.Errorf("model %s has no factors", name)
+			}
+		}
+	})
+}
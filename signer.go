@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Signer signs the query string of a SIGNED request
+type Signer interface {
+	Sign(payload string) (string, error)
+}
+
+// hmacSigner signs with the API secret (HMAC-SHA256, hex)
+type hmacSigner struct {
+	secret string
+}
+
+// Sign returns the hex HMAC-SHA256 of payload
+func (s hmacSigner) Sign(payload string) (string, error) {
+	return createSignature(payload, s.secret), nil
+}
+
+// rsaSigner signs with an RSA private key (PKCS#1 v1.5 over SHA-256, base64)
+type rsaSigner struct {
+	key *rsa.PrivateKey
+}
+
+// Sign returns the base64 RSA signature of payload
+func (s rsaSigner) Sign(payload string) (string, error) {
+	digest := sha256.Sum256([]byte(payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// ed25519Signer signs with an Ed25519 private key (base64)
+type ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// Sign returns the base64 Ed25519 signature of payload
+func (s ed25519Signer) Sign(payload string) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, []byte(payload))), nil
+}
+
+// sign signs a payload with the client's signer, falling back to HMAC with SecretKey
+func (c *BinanceClient) sign(payload string) (string, error) {
+	if c.Signer == nil {
+		return createSignature(payload, c.SecretKey), nil
+	}
+	return c.Signer.Sign(payload)
+}
+
+// loadPrivateKeyPEM reads an unencrypted PKCS#8 (RSA or Ed25519) or PKCS#1 (RSA) private key
+func loadPrivateKeyPEM(path string) (crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s ไม่ใช่ไฟล์ PEM", path)
+	}
+	if strings.Contains(block.Type, "ENCRYPTED") {
+		return nil, fmt.Errorf("%s ถูกเข้ารหัสไว้ - ใช้ private key ที่ไม่มีรหัสผ่าน", path)
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%s: ไม่รู้จักรูปแบบ private key (%s)", path, block.Type)
+}
+
+// newSigner builds the signer for keyType ("hmac", "rsa" or "ed25519")
+func newSigner(keyType, secret, keyPath string) (Signer, error) {
+	switch strings.ToLower(keyType) {
+	case "", "hmac":
+		if secret == "" {
+			return nil, fmt.Errorf("กรุณาตั้งค่า BINANCE_API_SECRET ในไฟล์ .env")
+		}
+		return hmacSigner{secret: secret}, nil
+
+	case "rsa", "ed25519":
+		if keyPath == "" {
+			return nil, fmt.Errorf("กรุณาตั้งค่า BINANCE_PRIVATE_KEY_PATH สำหรับ key แบบ %s", keyType)
+		}
+		key, err := loadPrivateKeyPEM(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("ไม่สามารถโหลด private key: %v", err)
+		}
+
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			if strings.EqualFold(keyType, "rsa") {
+				return rsaSigner{key: k}, nil
+			}
+		case ed25519.PrivateKey:
+			if strings.EqualFold(keyType, "ed25519") {
+				return ed25519Signer{key: k}, nil
+			}
+		}
+		return nil, fmt.Errorf("private key ใน %s ไม่ใช่แบบ %s (BINANCE_KEY_TYPE)", keyPath, keyType)
+	}
+
+	return nil, fmt.Errorf("BINANCE_KEY_TYPE ไม่ถูกต้อง: %s (hmac, rsa หรือ ed25519)", keyType)
+}
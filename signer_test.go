@@ -0,0 +1,134 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const signerPayload = "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559"
+
+// writePEM saves a PEM block in a temporary directory and returns its path
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHMACSigner(t *testing.T) {
+	// Example from the Binance API documentation
+	signer, err := newSigner("hmac", "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := signer.Sign(signerPayload)
+	if want := "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71"; got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+}
+
+func TestKeySigners(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPKCS8, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	edPKCS8, _ := x509.MarshalPKCS8PrivateKey(edKey)
+
+	verifyRSA := func(signature []byte) error {
+		digest := sha256.Sum256([]byte(signerPayload))
+		return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], signature)
+	}
+	verifyEd25519 := func(signature []byte) error {
+		if !ed25519.Verify(edPublic, []byte(signerPayload), signature) {
+			return os.ErrInvalid
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name      string
+		keyType   string
+		blockType string
+		der       []byte
+		verify    func([]byte) error
+		wantErr   bool
+	}{
+		{"rsa pkcs8", "rsa", "PRIVATE KEY", rsaPKCS8, verifyRSA, false},
+		{"rsa pkcs1", "RSA", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), verifyRSA, false},
+		{"ed25519 pkcs8", "ed25519", "PRIVATE KEY", edPKCS8, verifyEd25519, false},
+		{"rsa key declared as ed25519", "ed25519", "PRIVATE KEY", rsaPKCS8, nil, true},
+		{"ed25519 key declared as rsa", "rsa", "PRIVATE KEY", edPKCS8, nil, true},
+		{"encrypted key", "rsa", "ENCRYPTED PRIVATE KEY", rsaPKCS8, nil, true},
+		{"garbage key", "rsa", "PRIVATE KEY", []byte("not a key"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := newSigner(tt.keyType, "", writePEM(t, tt.blockType, tt.der))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got signer %T", signer)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded, err := signer.Sign(signerPayload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signature, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatalf("signature %q is not base64: %v", encoded, err)
+			}
+			if err := tt.verify(signature); err != nil {
+				t.Errorf("signature does not verify with the public key: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewSignerConfigErrors(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(notPEM, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		keyType string
+		secret  string
+		path    string
+	}{
+		{"hmac without a secret", "hmac", "", ""},
+		{"rsa without a key path", "rsa", "", ""},
+		{"missing key file", "ed25519", "", filepath.Join(t.TempDir(), "missing.pem")},
+		{"not a PEM file", "ed25519", "", notPEM},
+		{"unknown key type", "dsa", "secret", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newSigner(tt.keyType, tt.secret, tt.path); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Levels fetched per side when estimating a market order's fill
+const slippageDepthLimit = 1000
+
+// SlippageConfig represents the market-order guard loaded from .env
+type SlippageConfig struct {
+	MaxImpact float64 // MAX_SLIPPAGE_PERCENT: worst acceptable average fill vs mid price, 0 = off
+	Action    string  // SLIPPAGE_ACTION: "limit" converts to a protected IOC limit, "refuse" rejects
+}
+
+// SlippageEstimate represents the expected fill of a market order against the book
+type SlippageEstimate struct {
+	Side          string  `json:"side"`
+	Quantity      float64 `json:"quantity"`
+	MidPrice      float64 `json:"midPrice"`
+	AvgPrice      float64 `json:"avgPrice"`
+	WorstPrice    float64 `json:"worstPrice"`    // deepest level the order reaches
+	ImpactPercent float64 `json:"impactPercent"` // average fill vs mid, positive = worse
+	Available     float64 `json:"available"`     // book quantity on the side the order takes
+	Action        string  `json:"action"`        // MARKET, LIMIT or REFUSED
+	LimitPrice    float64 `json:"limitPrice,omitempty"`
+}
+
+// loadSlippageConfig reads the slippage limit and action from .env
+func loadSlippageConfig() SlippageConfig {
+	action := strings.ToLower(os.Getenv("SLIPPAGE_ACTION"))
+	if action != "refuse" {
+		action = "limit"
+	}
+	return SlippageConfig{
+		MaxImpact: envFloat("MAX_SLIPPAGE_PERCENT", 2),
+		Action:    action,
+	}
+}
+
+// estimateSlippage walks the opposite side of the book until quantity is filled
+func estimateSlippage(book *OrderBook, side string, quantity float64) SlippageEstimate {
+	estimate := SlippageEstimate{Side: side, Quantity: quantity}
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return estimate
+	}
+	estimate.MidPrice = (book.Bids[0].Price + book.Asks[0].Price) / 2
+
+	levels := book.Asks
+	if side == "SELL" {
+		levels = book.Bids
+	}
+
+	remaining, cost := quantity, 0.0
+	for _, level := range levels {
+		estimate.Available += level.Quantity
+		if remaining <= 0 {
+			continue
+		}
+		take := min64(level.Quantity, remaining)
+		cost += take * level.Price
+		remaining -= take
+		estimate.WorstPrice = level.Price
+	}
+
+	if filled := quantity - remaining; filled > 0 {
+		estimate.AvgPrice = cost / filled
+		estimate.ImpactPercent = (estimate.AvgPrice - estimate.MidPrice) / estimate.MidPrice * 100
+		if side == "SELL" {
+			estimate.ImpactPercent = -estimate.ImpactPercent
+		}
+	}
+	return estimate
+}
+
+// checkSlippage estimates a market order's impact and decides whether it is sent as is,
+// converted to a limit at the worst acceptable price, or refused. A closing sell always goes out
+// as a market order: an IOC limit could leave part of the position open below its stop
+func checkSlippage(symbol, side, quantity string, closing bool) (*SlippageEstimate, error) {
+	config := loadSlippageConfig()
+	if config.MaxImpact <= 0 {
+		return nil, nil
+	}
+
+	qty, err := strconv.ParseFloat(quantity, 64)
+	if err != nil {
+		return nil, &RiskError{Rule: "QUANTITY", Reason: fmt.Sprintf("จำนวนไม่ถูกต้อง %q", quantity)}
+	}
+
+	book, err := getOrderBook(symbol, slippageDepthLimit)
+	if err != nil {
+		// Never trap an exit because the book could not be read
+		if side == "SELL" {
+			fmt.Printf("⚠️ ไม่สามารถดึง order book %s (%v) - ส่ง market sell โดยไม่ประเมิน slippage\n", symbol, err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("slippage guard: ไม่สามารถดึง order book %s: %v", symbol, err)
+	}
+
+	exitOnly := closing && side == "SELL"
+	estimate := estimateSlippage(book, side, qty)
+	if estimate.MidPrice <= 0 {
+		if exitOnly {
+			fmt.Printf("⚠️ order book %s ว่าง - ส่ง market sell ปิด position โดยไม่ประเมิน slippage\n", symbol)
+			return nil, nil
+		}
+		return nil, &RiskError{Rule: "SLIPPAGE", Reason: fmt.Sprintf("order book %s ว่าง", symbol)}
+	}
+
+	shortBook := estimate.Available < qty
+	estimate.Action = "MARKET"
+	if (estimate.ImpactPercent > config.MaxImpact || shortBook) && !exitOnly {
+		if config.Action == "refuse" {
+			estimate.Action = "REFUSED"
+		} else {
+			estimate.Action = "LIMIT"
+			estimate.LimitPrice = estimate.MidPrice * (1 + config.MaxImpact/100)
+			if side == "SELL" {
+				estimate.LimitPrice = estimate.MidPrice * (1 - config.MaxImpact/100)
+			}
+		}
+	}
+
+	fmt.Printf("📏 Slippage %s %s %s: เฉลี่ย $%.8f vs mid $%.8f (%+.2f%%, สูงสุด %.2f%%) → %s\n",
+		side, quantity, symbol, estimate.AvgPrice, estimate.MidPrice, estimate.ImpactPercent, config.MaxImpact, estimate.Action)
+	if shortBook {
+		fmt.Printf("   ⚠️ order book มีเพียง %s ในฝั่งที่ต้องการ\n", strconv.FormatFloat(estimate.Available, 'f', -1, 64))
+	}
+	if exitOnly && estimate.ImpactPercent > config.MaxImpact {
+		fmt.Println("   ⚠️ ขายปิด position - ส่ง market ทั้งจำนวนแม้เกินเพดาน slippage")
+	}
+
+	if estimate.Action == "REFUSED" {
+		return &estimate, &RiskError{Rule: "SLIPPAGE", Reason: fmt.Sprintf(
+			"คาดว่า fill แย่กว่า mid %.2f%% เกินเพดาน %.2f%% (MAX_SLIPPAGE_PERCENT)", estimate.ImpactPercent, config.MaxImpact)}
+	}
+	return &estimate, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateSlippage(t *testing.T) {
+	book := &OrderBook{
+		Bids: []BookLevel{{Price: 99, Quantity: 1}, {Price: 98, Quantity: 2}},
+		Asks: []BookLevel{{Price: 101, Quantity: 1}, {Price: 102, Quantity: 2}},
+	}
+
+	tests := []struct {
+		name      string
+		book      *OrderBook
+		side      string
+		quantity  float64
+		want      SlippageEstimate
+		wantEmpty bool
+	}{
+		{
+			name:     "buy inside the best ask",
+			book:     book,
+			side:     "BUY",
+			quantity: 1,
+			want:     SlippageEstimate{MidPrice: 100, AvgPrice: 101, WorstPrice: 101, ImpactPercent: 1, Available: 3},
+		},
+		{
+			name:     "buy walks two levels",
+			book:     book,
+			side:     "BUY",
+			quantity: 2,
+			want:     SlippageEstimate{MidPrice: 100, AvgPrice: 101.5, WorstPrice: 102, ImpactPercent: 1.5, Available: 3},
+		},
+		{
+			name:     "sell impact is positive when worse than mid",
+			book:     book,
+			side:     "SELL",
+			quantity: 3,
+			want:     SlippageEstimate{MidPrice: 100, AvgPrice: 295.0 / 3, WorstPrice: 98, ImpactPercent: 5.0 / 3, Available: 3},
+		},
+		{
+			name:     "short book averages only what fills",
+			book:     book,
+			side:     "BUY",
+			quantity: 5,
+			want:     SlippageEstimate{MidPrice: 100, AvgPrice: 305.0 / 3, WorstPrice: 102, ImpactPercent: 5.0 / 3, Available: 3},
+		},
+		{
+			name:      "empty side gives no estimate",
+			book:      &OrderBook{Bids: book.Bids},
+			side:      "BUY",
+			quantity:  1,
+			wantEmpty: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimateSlippage(tt.book, tt.side, tt.quantity)
+			if got.Side != tt.side || got.Quantity != tt.quantity {
+				t.Errorf("side/quantity = %s/%v, want %s/%v", got.Side, got.Quantity, tt.side, tt.quantity)
+			}
+			if tt.wantEmpty {
+				if got.MidPrice != 0 || got.AvgPrice != 0 {
+					t.Errorf("estimate = %+v, want empty", got)
+				}
+				return
+			}
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"MidPrice", got.MidPrice, tt.want.MidPrice},
+				{"AvgPrice", got.AvgPrice, tt.want.AvgPrice},
+				{"WorstPrice", got.WorstPrice, tt.want.WorstPrice},
+				{"ImpactPercent", got.ImpactPercent, tt.want.ImpactPercent},
+				{"Available", got.Available, tt.want.Available},
+			}
+			for _, c := range checks {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeframes = "15m,1h,4h,1d"
+	// The analysis the other timeframes confirm, unless BASE_TIMEFRAME says otherwise
+	defaultBaseTimeframe = "1d"
+	// Klines fetched per timeframe
+	timeframeKlineLimit = 144
+	// The base timeframe should have at least this many candles
+	minAnalysisKlines = 30
+)
+
+// Trend labels used by timeframe views
+const (
+	trendUp       = "ขาขึ้น"
+	trendDown     = "ขาลง"
+	trendSideways = "ไซด์เวย์"
+)
+
+// intervalDuration returns the length of one candle of a Binance kline interval
+func intervalDuration(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("interval ไม่ถูกต้อง: %q", interval)
+	}
+
+	var count int
+	if _, err := fmt.Sscanf(interval[:len(interval)-1], "%d", &count); err != nil || count <= 0 {
+		return 0, fmt.Errorf("interval ไม่ถูกต้อง: %q", interval)
+	}
+
+	units := map[byte]time.Duration{
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+		'M': 30 * 24 * time.Hour,
+	}
+	unit, ok := units[interval[len(interval)-1]]
+	if !ok {
+		return 0, fmt.Errorf("interval ไม่ถูกต้อง: %q", interval)
+	}
+	return time.Duration(count) * unit, nil
+}
+
+// loadTimeframes reads the comma-separated TIMEFRAME list, shortest first
+func loadTimeframes() []string {
+	raw := os.Getenv("TIMEFRAME")
+	if raw == "" {
+		raw = defaultTimeframes
+	}
+
+	var timeframes []string
+	seen := make(map[string]bool)
+	for _, interval := range strings.Split(raw, ",") {
+		interval = strings.TrimSpace(interval)
+		if interval == "" || seen[interval] {
+			continue
+		}
+		if _, err := intervalDuration(interval); err != nil {
+			fmt.Printf("⚠️ TIMEFRAME: %v - ข้าม\n", err)
+			continue
+		}
+		seen[interval] = true
+		timeframes = append(timeframes, interval)
+	}
+	if len(timeframes) == 0 {
+		timeframes = strings.Split(defaultTimeframes, ",")
+	}
+
+	sort.Slice(timeframes, func(i, j int) bool {
+		a, _ := intervalDuration(timeframes[i])
+		b, _ := intervalDuration(timeframes[j])
+		return a < b
+	})
+	return timeframes
+}
+
+// loadBaseTimeframe reads BASE_TIMEFRAME; TIMEFRAME only adds confirming views, so an existing
+// single-interval TIMEFRAME setting does not move the base analysis off daily candles
+func loadBaseTimeframe() string {
+	base := strings.TrimSpace(os.Getenv("BASE_TIMEFRAME"))
+	if base == "" {
+		return defaultBaseTimeframe
+	}
+	if _, err := intervalDuration(base); err != nil {
+		fmt.Printf("⚠️ BASE_TIMEFRAME: %v - ใช้ %s\n", err, defaultBaseTimeframe)
+		return defaultBaseTimeframe
+	}
+	return base
+}
+
+// newTimeframeView summarizes one timeframe's analysis
+func newTimeframeView(analysis AINewCoinAnalysis, candles int) TimeframeView {
+	return TimeframeView{
+		Interval:          analysis.TimeFrame,
+		Candles:           candles,
+		Trend:             analysis.Trend,
+		Support:           analysis.Support,
+		Resistance:        analysis.Resistance,
+		RSI:               analysis.Indicators.RSI,
+		ShouldAccumulate:  analysis.ShouldAccumulate,
+		ReverseSignal:     analysis.ReverseSignal,
+		RecommendedAction: analysis.RecommendedAction,
+	}
+}
+
+// bullish reports whether a timeframe supports entering
+func (v TimeframeView) bullish() bool {
+	return v.Trend == trendUp || v.ShouldAccumulate || v.ReverseSignal
+}
+
+// combineTimeframes scores agreement of the other views with the base analysis and only lets it
+// recommend accumulating when enough of them agree; the base view never votes for itself
+func combineTimeframes(base *AINewCoinAnalysis, views []TimeframeView, minAlignment float64) {
+	base.Timeframes = views
+
+	agreeing, confirming := 0, 0
+	for _, view := range views {
+		if view.Interval == base.TimeFrame {
+			continue
+		}
+		confirming++
+		if view.bullish() {
+			agreeing++
+		}
+	}
+	if confirming == 0 {
+		return
+	}
+	base.AlignmentScore = float64(agreeing) / float64(confirming) * 100
+
+	if !base.ShouldAccumulate {
+		return
+	}
+	if base.AlignmentScore < minAlignment {
+		base.ShouldAccumulate = false
+		base.Confidence = "ต่ำ"
+		base.RecommendedAction = "รอ"
+		base.TechnicalSummary += fmt.Sprintf(", ไทม์เฟรมยืนยันเพียง %d/%d", agreeing, confirming)
+	} else if agreeing == confirming && base.Confidence == "ปานกลาง" {
+		base.Confidence = "สูง"
+	}
+}
+
+// timeframeKlines represents one interval's fetched history
+type timeframeKlines struct {
+	interval string
+	klines   []Kline
+}
+
+// analyzeCoinTimeframes runs performAIAnalysis on the base interval and every timeframe. The base
+// analysis uses baseInterval when it has minAnalysisKlines candles, otherwise the longest shorter
+// interval that does, and the others must agree with it; when even that history is too short it
+// falls back to the intraday intervals
+func analyzeCoinTimeframes(client *BinanceClient, coin CoinInfo, timeframes []string, baseInterval string) (*AINewCoinAnalysis, error) {
+	fetch := func(intervals []string) ([]timeframeKlines, error) {
+		var series []timeframeKlines
+		for _, interval := range intervals {
+			klines, err := getKlines(client, coin.Symbol, interval, timeframeKlineLimit)
+			if err != nil {
+				return nil, fmt.Errorf("ไม่สามารถดึงข้อมูล %s (%s): %v", coin.Symbol, interval, err)
+			}
+			series = append(series, timeframeKlines{interval: interval, klines: klines})
+		}
+		return series, nil
+	}
+
+	// The base interval is always fetched, even when TIMEFRAME does not list it
+	intervals := append([]string(nil), timeframes...)
+	listed := false
+	for _, interval := range timeframes {
+		listed = listed || interval == baseInterval
+	}
+	if !listed {
+		intervals = append(intervals, baseInterval)
+	}
+	series, err := fetch(intervals)
+	if err != nil {
+		return nil, err
+	}
+	sortTimeframeSeries(series)
+
+	base := pickBaseTimeframe(series, baseInterval)
+	if base < 0 {
+		// Too young for every configured interval: try finer ones
+		configured := make(map[string]bool)
+		for _, interval := range intervals {
+			configured[interval] = true
+		}
+		var extra []string
+		for _, interval := range intradayFallbacks {
+			if !configured[interval] {
+				extra = append(extra, interval)
+			}
+		}
+		more, err := fetch(extra)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, more...)
+		sortTimeframeSeries(series)
+		base = pickBaseTimeframe(series, baseInterval)
+	}
+	if base < 0 {
+		return nil, fmt.Errorf("ข้อมูล %s ไม่เพียงพอสำหรับวิเคราะห์", coin.Symbol)
+	}
+
+	// The volume profile shapes only the base analysis; other views keep their own structure levels
+	viewCoin := coin
+	viewCoin.Profile = nil
+
+	var analysis AINewCoinAnalysis
+	var views []TimeframeView
+	for i, s := range series {
+		if len(s.klines) < minViewKlines {
+			continue
+		}
+		analysisCoin := viewCoin
+		if i == base {
+			analysisCoin = coin
+		}
+		view := performAIAnalysis(analysisCoin, s.klines, s.interval)
+		if i == base {
+			analysis = view
+		}
+		views = append(views, newTimeframeView(view, len(s.klines)))
+	}
+
+	if duration, _ := intervalDuration(analysis.TimeFrame); duration < 24*time.Hour {
+		analysis.Intraday = true
+	}
+	combineTimeframes(&analysis, views, envFloat("MIN_TIMEFRAME_ALIGNMENT", 60))
+	return &analysis, nil
+}
+
+// sortTimeframeSeries orders series shortest interval first
+func sortTimeframeSeries(series []timeframeKlines) {
+	sort.Slice(series, func(i, j int) bool {
+		a, _ := intervalDuration(series[i].interval)
+		b, _ := intervalDuration(series[j].interval)
+		return a < b
+	})
+}
+
+// pickBaseTimeframe returns the index of the longest interval up to preferred with minAnalysisKlines
+// candles; failing that the one with the most candles above minViewKlines, or -1. Series are shortest first
+func pickBaseTimeframe(series []timeframeKlines, preferred string) int {
+	limit, _ := intervalDuration(preferred)
+	for i := len(series) - 1; i >= 0; i-- {
+		duration, _ := intervalDuration(series[i].interval)
+		if duration <= limit && len(series[i].klines) >= minAnalysisKlines {
+			return i
+		}
+	}
+
+	best := -1
+	for i, s := range series {
+		if len(s.klines) >= minViewKlines && (best < 0 || len(s.klines) > len(series[best].klines)) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const tradeStoreFile = "trades.json"
+
+// myTrades weighs 20; this pause keeps a full pair scan under the 6000/min weight limit
+const tradeDiscoveryDelay = 250 * time.Millisecond
+
+// TradeStore represents the locally stored trade history per symbol
+type TradeStore struct {
+	Trades    map[string][]Trade `json:"trades"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// loadTradeStore reads the stored trade history
+func loadTradeStore() (*TradeStore, error) {
+	store := &TradeStore{}
+	if _, err := loadState(tradeStoreFile, store); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลดประวัติการเทรด: %v", err)
+	}
+	if store.Trades == nil {
+		store.Trades = map[string][]Trade{}
+	}
+	return store, nil
+}
+
+// save persists the trade history
+func (s *TradeStore) save() error {
+	s.UpdatedAt = time.Now()
+	return saveState(tradeStoreFile, s)
+}
+
+// symbols returns every symbol with stored trades
+func (s *TradeStore) symbols() []string {
+	symbols := make([]string, 0, len(s.Trades))
+	for symbol := range s.Trades {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// importTrades fetches trades newer than the last stored one for a symbol
+func (s *TradeStore) importTrades(client *BinanceClient, symbol string) (int, error) {
+	existing := s.Trades[symbol]
+	fromID := int64(0)
+	if len(existing) > 0 {
+		fromID = existing[len(existing)-1].ID + 1
+	}
+
+	imported := 0
+	for {
+		trades, err := getMyTrades(client, symbol, fromID)
+		if err != nil {
+			return imported, err
+		}
+		if len(trades) == 0 {
+			break
+		}
+
+		existing = append(existing, trades...)
+		imported += len(trades)
+		fromID = trades[len(trades)-1].ID + 1
+
+		if len(trades) < 1000 {
+			break
+		}
+	}
+
+	if imported > 0 {
+		s.Trades[symbol] = existing
+	}
+	return imported, nil
+}
+
+// tradedSymbolsSince scans the USDT pairs not in known for ones the account traded at or after since,
+// so coins bought and fully sold between imports are not missed
+func tradedSymbolsSince(client *BinanceClient, since time.Time, known map[string]bool) ([]string, error) {
+	exchangeInfo, err := getExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึงรายการคู่เทรด: %v", err)
+	}
+
+	var pairs []string
+	for _, info := range exchangeInfo.Symbols {
+		// Halted and delisted pairs still keep their trade history
+		if info.QuoteAsset == "USDT" && !known[info.Symbol] && !isExcludedSymbol(info.Symbol) {
+			pairs = append(pairs, info.Symbol)
+		}
+	}
+
+	fmt.Printf("🔎 กำลังค้นหาคู่ที่เคยเทรดจาก %d คู่ USDT...\n", len(pairs))
+	var traded []string
+	for i, symbol := range pairs {
+		if i > 0 {
+			time.Sleep(tradeDiscoveryDelay)
+		}
+		trade, err := getLatestTrade(client, symbol)
+		if err != nil {
+			fmt.Printf("⚠️ ไม่สามารถตรวจสอบ %s: %v\n", symbol, err)
+			continue
+		}
+		if trade != nil && !time.UnixMilli(trade.Time).Before(since) {
+			traded = append(traded, symbol)
+		}
+	}
+	return traded, nil
+}
+
+// journalSymbolsSince returns the symbols of journaled orders updated at or after since
+func journalSymbolsSince(since time.Time) ([]string, error) {
+	journal, err := loadJournal()
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลด order journal: %v", err)
+	}
+	var symbols []string
+	for _, entry := range journal.Entries {
+		if !entry.UpdatedAt.Before(since) {
+			symbols = append(symbols, entry.Symbol)
+		}
+	}
+	return symbols, nil
+}
+
+// tradeSymbolsToImport lists held USDT-quoted coins, stored symbols, symbols the bot ordered since the
+// last import and any extra symbols. Every USDT pair is scanned only on the first import or with all
+func tradeSymbolsToImport(client *BinanceClient, store *TradeStore, extra []string, all bool) ([]string, error) {
+	seen := map[string]bool{}
+	var symbols []string
+	add := func(symbol string) {
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	balances, err := getAccountBalances(client)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถดึงยอดเงิน: %v", err)
+	}
+	for _, balance := range balances {
+		if balance.Asset == "USDT" || isExcludedSymbol(balance.Asset+"USDT") {
+			continue
+		}
+		add(balance.Asset + "USDT")
+	}
+
+	for _, symbol := range store.symbols() {
+		add(symbol)
+	}
+	for _, symbol := range extra {
+		add(strings.ToUpper(symbol))
+	}
+
+	journaled, err := journalSymbolsSince(store.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, symbol := range journaled {
+		add(symbol)
+	}
+
+	// The full pair scan costs hundreds of weighted requests; a zero UpdatedAt (first import)
+	// matches every pair the account ever traded
+	if all || store.UpdatedAt.IsZero() {
+		traded, err := tradedSymbolsSince(client, store.UpdatedAt, seen)
+		if err != nil {
+			return nil, err
+		}
+		for _, symbol := range traded {
+			add(symbol)
+		}
+	}
+
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// syncTradeHistory imports new trades for held, stored, journaled and extra symbols; all also
+// scans every USDT pair for trades since the last import
+func syncTradeHistory(client *BinanceClient, extra []string, all bool) (*TradeStore, error) {
+	store, err := loadTradeStore()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, err := tradeSymbolsToImport(client, store, extra, all)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("📥 กำลังนำเข้าประวัติการเทรด %d สัญลักษณ์...\n", len(symbols))
+	for _, symbol := range symbols {
+		imported, err := store.importTrades(client, symbol)
+		if err != nil {
+			// Held assets without a USDT market are simply skipped
+			fmt.Printf("⚠️ ไม่สามารถนำเข้า %s: %v\n", symbol, err)
+			continue
+		}
+		if imported > 0 {
+			fmt.Printf("   • %s: +%d เทรด\n", symbol, imported)
+		}
+	}
+
+	if err := store.save(); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถบันทึกประวัติการเทรด: %v", err)
+	}
+	return store, nil
+}
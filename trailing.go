@@ -0,0 +1,487 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	trailingStateFile = "trailing.json"
+	// ATR is recalculated at most this often
+	trailATRRefresh = time.Hour
+)
+
+// TrailingStop represents a locally managed stop that follows the highest price seen
+type TrailingStop struct {
+	Symbol        string    `json:"symbol"`
+	Mode          string    `json:"mode"` // "percent" or "atr"
+	Percent       float64   `json:"percent,omitempty"`
+	ATRMultiple   float64   `json:"atrMultiple,omitempty"`
+	ATR           float64   `json:"atr,omitempty"`
+	ATRUpdatedAt  time.Time `json:"atrUpdatedAt,omitempty"`
+	HighWaterMark float64   `json:"highWaterMark"`
+	StopPrice     float64   `json:"stopPrice"`
+	Quantity      float64   `json:"quantity,omitempty"` // position the stop protects, reduced by each fill; 0 = the whole free balance
+	Status        string    `json:"status"`             // ACTIVE, TRIGGERED or CLOSED (position no longer held)
+	OrderID       string    `json:"orderId,omitempty"`
+	TriggerPrice  float64   `json:"triggerPrice,omitempty"`
+	TriggeredAt   time.Time `json:"triggeredAt,omitempty"`
+	Sells         int       `json:"sells,omitempty"` // sell orders placed; keys the next sell's client order ID
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// TrailingState represents every trailing stop, keyed by symbol
+type TrailingState struct {
+	Stops map[string]*TrailingStop `json:"stops"`
+}
+
+// parseTrailSpec parses "10%" (percent below the high) or "2.5atr" (ATR multiple)
+func parseTrailSpec(spec string) (mode string, value float64, err error) {
+	spec = strings.ToLower(spec)
+	switch {
+	case strings.HasSuffix(spec, "%"):
+		mode = "percent"
+		value, err = strconv.ParseFloat(strings.TrimSuffix(spec, "%"), 64)
+		if err == nil && (value <= 0 || value >= 100) {
+			err = errors.New("out of range")
+		}
+	case strings.HasSuffix(spec, "atr"):
+		mode = "atr"
+		value, err = strconv.ParseFloat(strings.TrimSuffix(spec, "atr"), 64)
+		if err == nil && value <= 0 {
+			err = errors.New("out of range")
+		}
+	default:
+		err = errors.New("unknown unit")
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("ระยะ trailing ไม่ถูกต้อง: %s (ใช้ เช่น 10%% หรือ 2.5atr)", spec)
+	}
+	return mode, value, nil
+}
+
+// loadTrailingState reads every trailing stop
+func loadTrailingState() (*TrailingState, error) {
+	state := &TrailingState{}
+	if _, err := loadState(trailingStateFile, state); err != nil {
+		return nil, fmt.Errorf("ไม่สามารถโหลด trailing stops: %v", err)
+	}
+	if state.Stops == nil {
+		state.Stops = map[string]*TrailingStop{}
+	}
+	return state, nil
+}
+
+// save persists the trailing stops
+func (s *TrailingState) save() error {
+	return saveState(trailingStateFile, s)
+}
+
+// sortedStops returns the stops ordered by symbol
+func (s *TrailingState) sortedStops() []*TrailingStop {
+	stops := make([]*TrailingStop, 0, len(s.Stops))
+	for _, stop := range s.Stops {
+		stops = append(stops, stop)
+	}
+	sort.Slice(stops, func(i, j int) bool {
+		return stops[i].Symbol < stops[j].Symbol
+	})
+	return stops
+}
+
+// refreshATR recalculates the ATR from TRAIL_ATR_INTERVAL klines when it is stale
+func (t *TrailingStop) refreshATR() error {
+	if t.Mode != "atr" || time.Since(t.ATRUpdatedAt) < trailATRRefresh {
+		return nil
+	}
+
+	interval := os.Getenv("TRAIL_ATR_INTERVAL")
+	if interval == "" {
+		interval = "1h"
+	}
+	period := envInt("TRAIL_ATR_PERIOD", 14)
+
+	klines, err := getKlines(&BinanceClient{}, t.Symbol, interval, period*3)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึงข้อมูล %s: %v", t.Symbol, err)
+	}
+	atr := calculateATR(klines, period)
+	if atr <= 0 {
+		return fmt.Errorf("ข้อมูล %s ไม่พอคำนวณ ATR", t.Symbol)
+	}
+
+	t.ATR = atr
+	t.ATRUpdatedAt = time.Now()
+	return nil
+}
+
+// stopDistance returns how far below the high-water mark the stop sits
+func (t *TrailingStop) stopDistance() float64 {
+	if t.Mode == "atr" {
+		return t.ATRMultiple * t.ATR
+	}
+	return t.HighWaterMark * t.Percent / 100
+}
+
+// update moves the high-water mark and stop with the price and reports whether the stop was hit
+func (t *TrailingStop) update(price float64) bool {
+	if price > t.HighWaterMark {
+		t.HighWaterMark = price
+		t.UpdatedAt = time.Now()
+	}
+
+	// The stop only ever ratchets up, even when ATR widens
+	if stop := t.HighWaterMark - t.stopDistance(); stop > t.StopPrice {
+		t.StopPrice = stop
+		t.UpdatedAt = time.Now()
+	}
+
+	return t.StopPrice > 0 && price <= t.StopPrice
+}
+
+// describe returns a short label of the trailing distance
+func (t *TrailingStop) describe() string {
+	if t.Mode == "atr" {
+		return fmt.Sprintf("%.2f×ATR", t.ATRMultiple)
+	}
+	return fmt.Sprintf("%.1f%%", t.Percent)
+}
+
+// sellTrailingPosition market-sells the stop's position, never more than the free balance. The stop only
+// becomes TRIGGERED once the position is sold; a partial fill leaves it ACTIVE so the remainder is retried
+func sellTrailingPosition(client *BinanceClient, stop *TrailingStop, price float64) error {
+	rules, err := getSymbolRules(stop.Symbol)
+	if err != nil {
+		return err
+	}
+
+	balances, err := getAccountBalances(client)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถดึงยอดเงิน: %v", err)
+	}
+
+	asset := getBaseCoin(stop.Symbol)
+	free := 0.0
+	for _, balance := range balances {
+		if balance.Asset == asset {
+			free = balance.Free
+		}
+	}
+
+	position := free
+	if stop.Quantity > 0 {
+		position = math.Min(stop.Quantity, free)
+	}
+	quantity := floorToStep(position, rules.StepSize)
+	if quantity < rules.MinQty || quantity*price < rules.MinNotional {
+		stop.Status = "CLOSED"
+		stop.Error = fmt.Sprintf("ไม่มี %s พอขาย (free %s)", asset, strconv.FormatFloat(free, 'f', -1, 64))
+		return nil
+	}
+
+	tag := OrderTag{Strategy: strategyTrailing, Key: fmt.Sprintf("%d|%d", stop.CreatedAt.UnixNano(), stop.Sells), Closing: true}
+	orderID, err := placeOrder(client, tag, stop.Symbol, "SELL", "MARKET", rules.formatQuantity(quantity), "")
+	if err != nil {
+		return err
+	}
+	stop.Sells++
+	stop.OrderID = orderID
+
+	order, err := getOrder(client, stop.Symbol, orderID)
+	if err != nil {
+		// The order went out; assuming it filled is safer than selling coins the stop does not own
+		stop.Status = "TRIGGERED"
+		stop.Error = fmt.Sprintf("ตรวจสอบจำนวนที่ขายไม่ได้: %v", err)
+		return nil
+	}
+
+	executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	if stop.Quantity > 0 {
+		stop.Quantity = math.Max(stop.Quantity-executed, 0)
+	}
+	remaining := quantity - executed
+	if remaining >= rules.MinQty && remaining*price >= rules.MinNotional {
+		stop.Error = fmt.Sprintf("ขายได้ %s จาก %s (%s) - เหลือ %s รอขายรอบถัดไป", order.ExecutedQty,
+			rules.formatQuantity(quantity), order.Status, rules.formatQuantity(remaining))
+		return nil
+	}
+
+	stop.Status = "TRIGGERED"
+	stop.Error = ""
+	return nil
+}
+
+// checkTrailingStops updates every active stop with the latest prices and sells the ones that are hit
+func checkTrailingStops(client *BinanceClient, prices map[string]float64) error {
+	state, err := loadTrailingState()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, stop := range state.sortedStops() {
+		if stop.Status != "ACTIVE" {
+			continue
+		}
+		price, ok := prices[stop.Symbol]
+		if !ok || price <= 0 {
+			continue
+		}
+
+		before := *stop
+		if err := stop.refreshATR(); err != nil {
+			fmt.Printf("⚠️ Trailing %s: %v\n", stop.Symbol, err)
+		}
+		if stop.Mode == "atr" && stop.ATR <= 0 {
+			continue
+		}
+
+		if stop.update(price) {
+			fmt.Printf("🔻 Trailing stop %s ถูกชน: ราคา $%.8f ≤ stop $%.8f (สูงสุด $%.8f)\n",
+				stop.Symbol, price, stop.StopPrice, stop.HighWaterMark)
+			stop.TriggerPrice = price
+			stop.TriggeredAt = time.Now()
+			if err := sellTrailingPosition(client, stop, price); err != nil {
+				// Stay active so the next check retries the sell
+				stop.Error = err.Error()
+				fmt.Printf("❌ ขาย %s ไม่สำเร็จ: %v\n", stop.Symbol, err)
+			} else if stop.Status == "TRIGGERED" {
+				fmt.Printf("✅ ขาย %s แล้ว (Order ID: %s)\n", stop.Symbol, stop.OrderID)
+			} else {
+				fmt.Printf("ℹ️ %s: %s\n", stop.Symbol, stop.Error)
+			}
+			stop.UpdatedAt = time.Now()
+		} else if stop.HighWaterMark > before.HighWaterMark {
+			fmt.Printf("📈 %s สูงสุดใหม่ $%.8f → stop $%.8f\n", stop.Symbol, stop.HighWaterMark, stop.StopPrice)
+		}
+
+		if *stop != before {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return state.save()
+}
+
+// addTrailingStop creates a stop or changes its distance, keeping the existing high-water mark
+func addTrailingStop(state *TrailingState, symbol, mode string, value, price float64) *TrailingStop {
+	stop, ok := state.Stops[symbol]
+	if !ok || stop.Status != "ACTIVE" {
+		stop = &TrailingStop{Symbol: symbol, Status: "ACTIVE", CreatedAt: time.Now()}
+		state.Stops[symbol] = stop
+	}
+
+	stop.Mode = mode
+	stop.Percent, stop.ATRMultiple = 0, 0
+	if mode == "atr" {
+		stop.ATRMultiple = value
+		stop.ATRUpdatedAt = time.Time{}
+	} else {
+		stop.Percent = value
+	}
+	// A new distance restarts the stop from the current high
+	stop.StopPrice = 0
+	if price > stop.HighWaterMark {
+		stop.HighWaterMark = price
+	}
+	stop.UpdatedAt = time.Now()
+
+	if err := stop.refreshATR(); err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+	stop.update(price)
+	return stop
+}
+
+// printTrailingStops shows every stop with its distance from the current price
+func printTrailingStops(state *TrailingState, prices map[string]float64) {
+	if len(state.Stops) == 0 {
+		fmt.Println("📭 ยังไม่มี trailing stop")
+		return
+	}
+
+	fmt.Println("🪜 Trailing stops:")
+	fmt.Println("สัญลักษณ์     | ระยะ      | สูงสุด         | Stop          | ราคาปัจจุบัน   | ห่าง stop | สถานะ")
+	fmt.Println("--------------|----------|---------------|---------------|---------------|----------|------")
+	for _, stop := range state.sortedStops() {
+		current := prices[stop.Symbol]
+		distance := 0.0
+		if current > 0 && stop.StopPrice > 0 {
+			distance = (current - stop.StopPrice) / current * 100
+		}
+
+		status := stop.Status
+		if stop.Status == "TRIGGERED" {
+			status += fmt.Sprintf(" @ $%.8f %s", stop.TriggerPrice, stop.TriggeredAt.Format("2006-01-02 15:04"))
+		}
+		if stop.Error != "" {
+			status += " (" + stop.Error + ")"
+		}
+
+		fmt.Printf("%-13s | %-8s | $%-12.8f | $%-12.8f | $%-12.8f | %7.1f%% | %s\n",
+			stop.Symbol, stop.describe(), stop.HighWaterMark, stop.StopPrice, current, distance, status)
+	}
+}
+
+// runTrailingLoop polls prices every TRAIL_POLL_INTERVAL seconds until SIGINT/SIGTERM
+func runTrailingLoop(client *BinanceClient) error {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	interval := time.Duration(envInt("TRAIL_POLL_INTERVAL", 5)) * time.Second
+	fmt.Printf("🪜 ติดตาม trailing stops ทุก %v (Ctrl+C เพื่อหยุด)\n", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		prices, err := getAllPrices()
+		if err != nil {
+			fmt.Printf("⚠️ ไม่สามารถดึงราคา: %v\n", err)
+		} else if err := checkTrailingStops(client, prices); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+		}
+
+		select {
+		case <-stop:
+			fmt.Println("\n💾 บันทึก high-water marks แล้ว ออกจากการติดตาม")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runTrailCommand handles: trail add SYMBOL|all SPEC [QTY] | trail remove SYMBOL | trail list | trail run
+func runTrailCommand(args []string) error {
+	usage := "ใช้งาน: trail add SYMBOL|all 10%|2.5atr [QTY] | trail remove SYMBOL | trail list | trail run"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	loadEnvFile(".env")
+
+	state, err := loadTrailingState()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			return errors.New(usage)
+		}
+		mode, value, err := parseTrailSpec(args[2])
+		if err != nil {
+			return err
+		}
+		prices, err := getAllPrices()
+		if err != nil {
+			return fmt.Errorf("ไม่สามารถดึงราคา: %v", err)
+		}
+
+		// Position each stop protects: an explicit QTY, otherwise the coin's balance when keys are set
+		positions := map[string]float64{}
+		var symbols []string
+		if strings.EqualFold(args[1], "all") {
+			// Every held position that trades against USDT
+			client, err := newClientFromEnv()
+			if err != nil {
+				return err
+			}
+			portfolio, err := getPortfolio(client, defaultDustThreshold)
+			if err != nil {
+				return err
+			}
+			for _, entry := range portfolio.Entries {
+				if _, ok := prices[entry.Asset+"USDT"]; ok && entry.Asset != "USDT" {
+					symbols = append(symbols, entry.Asset+"USDT")
+					positions[entry.Asset+"USDT"] = entry.Total
+				}
+			}
+			if len(symbols) == 0 {
+				fmt.Println("📭 ไม่มีเหรียญที่ถืออยู่")
+				return nil
+			}
+		} else {
+			symbol := strings.ToUpper(args[1])
+			symbols = []string{symbol}
+			if len(args) > 3 {
+				quantity, err := strconv.ParseFloat(args[3], 64)
+				if err != nil || quantity <= 0 {
+					return fmt.Errorf("จำนวนไม่ถูกต้อง: %s", args[3])
+				}
+				positions[symbol] = quantity
+			} else if client, err := newClientFromEnv(); err == nil {
+				if balances, err := getAccountBalances(client); err == nil {
+					for _, balance := range balances {
+						if balance.Asset == getBaseCoin(symbol) {
+							positions[symbol] = balance.Free + balance.Locked
+						}
+					}
+				}
+			}
+		}
+
+		for _, symbol := range symbols {
+			price, ok := prices[symbol]
+			if !ok {
+				return fmt.Errorf("ไม่พบราคา %s", symbol)
+			}
+			stop := addTrailingStop(state, symbol, mode, value, price)
+			if quantity, ok := positions[symbol]; ok {
+				stop.Quantity = quantity
+			}
+			fmt.Printf("✅ Trailing stop %s %s: สูงสุด $%.8f → stop $%.8f\n",
+				symbol, stop.describe(), stop.HighWaterMark, stop.StopPrice)
+			if stop.Quantity == 0 {
+				fmt.Println("   ⚠️ ไม่ทราบขนาด position - จะขายยอด free ทั้งหมดเมื่อโดน stop")
+			}
+		}
+
+	case "remove":
+		if len(args) < 2 {
+			return errors.New(usage)
+		}
+		symbol := strings.ToUpper(args[1])
+		if _, ok := state.Stops[symbol]; !ok {
+			return fmt.Errorf("ไม่พบ trailing stop สำหรับ %s", symbol)
+		}
+		delete(state.Stops, symbol)
+		fmt.Printf("✅ ลบ trailing stop %s แล้ว\n", symbol)
+
+	case "list":
+		prices, err := getAllPrices()
+		if err != nil {
+			fmt.Printf("⚠️ ไม่สามารถดึงราคา: %v\n", err)
+		}
+		printTrailingStops(state, prices)
+		return nil
+
+	case "run":
+		client, err := newClientFromEnv()
+		if err != nil {
+			return err
+		}
+		if err := reconcileOnStartup(client); err != nil {
+			return err
+		}
+		return runTrailingLoop(client)
+
+	default:
+		return errors.New(usage)
+	}
+
+	return state.save()
+}
@@ -8,6 +8,7 @@ import (
 type BinanceClient struct {
 	APIKey    string
 	SecretKey string
+	Signer    Signer // nil signs with HMAC using SecretKey
 }
 
 // CoinInfo represents information about a scanned coin
@@ -18,8 +19,12 @@ type CoinInfo struct {
 	Volume24h   float64
 	PriceChange float64
 	Score       float64
+	ScoreModel  string        // scoring model and version that produced Score, e.g. legacy@1
+	Breakdown   []FactorScore // points per factor, adding up to Score
 	Reason      string
-	AgeDays     int // จำนวนวันที่เข้า listing
+	AgeDays     int              // จำนวนวันที่เข้า listing
+	Recovery    *RecoveryProfile // post-listing phase, measured by the scan when RequireRecovery is on
+	Profile     *VolumeProfile   // volume by price since listing, built before the AI analysis
 	LastUpdated time.Time
 }
 
@@ -30,8 +35,10 @@ type ScanCriteria struct {
 	MinPrice        float64
 	MinPriceChange  float64
 	MaxPriceChange  float64
-	MinAge          int
-	RequireRecovery bool
+	MinAge          int // listing age window in days, MaxAge 0 = no upper bound
+	MaxAge          int
+	RequireRecovery bool // keep only coins whose post-listing phase is in Recovery.Phases
+	Recovery        RecoveryConfig
 	MinScore        float64
 	MaxResults      int
 }
@@ -64,23 +71,80 @@ type AIAnalysis struct {
 
 // AINewCoinAnalysis represents AI analysis for new coins accumulation
 type AINewCoinAnalysis struct {
-	Symbol            string    `json:"symbol"`
-	Price             float64   `json:"price"`
-	AgeDays           int       `json:"ageDays"`
-	ShouldAccumulate  bool      `json:"shouldAccumulate"`
-	ReverseSignal     bool      `json:"reverseSignal"`
-	Confidence        string    `json:"confidence"`        // "สูง", "ปานกลาง", "ต่ำ"
-	RiskLevel         string    `json:"riskLevel"`         // "ต่ำ", "ปานกลาง", "สูง"
-	RecommendedAction string    `json:"recommendedAction"` // "สะสม", "รอ", "หลีกเลี่ยง"
-	AccumulationRange []float64 `json:"accumulationRange"` // [min_price, max_price]
-	StopLoss          float64   `json:"stopLoss"`
-	ProfitTarget      []float64 `json:"profitTarget"` // [target1, target2, target3]
-	TechnicalSummary  string    `json:"technicalSummary"`
-	MarketSentiment   string    `json:"marketSentiment"`
-	VolumeAnalysis    string    `json:"volumeAnalysis"`
-	PriceAction       string    `json:"priceAction"`
-	TimeFrame         string    `json:"timeFrame"`
-	LastUpdate        time.Time `json:"lastUpdate"`
+	Symbol               string        `json:"symbol"`
+	Price                float64       `json:"price"`
+	AgeDays              int           `json:"ageDays"`
+	Score                float64       `json:"score"`
+	ScoreModel           string        `json:"scoreModel"`
+	ScoreBreakdown       []FactorScore `json:"scoreBreakdown"`
+	ShouldAccumulate     bool          `json:"shouldAccumulate"`
+	ReverseSignal        bool          `json:"reverseSignal"`
+	Confidence           string        `json:"confidence"`        // "สูง", "ปานกลาง", "ต่ำ"
+	RiskLevel            string        `json:"riskLevel"`         // "ต่ำ", "ปานกลาง", "สูง"
+	RecommendedAction    string        `json:"recommendedAction"` // "สะสม", "รอ", "หลีกเลี่ยง"
+	AccumulationRange    []float64     `json:"accumulationRange"` // [min_price, max_price]
+	StopLoss             float64       `json:"stopLoss"`
+	ProfitTarget         []float64     `json:"profitTarget"`         // [target1, target2, target3]
+	Levels               TradeLevels   `json:"levels"`               // stop and targets with ATR distance and reward-to-risk
+	ProfitTargetGrossPct []float64     `json:"profitTargetGrossPct"` // return to each target before fees
+	ProfitTargetNetPct   []float64     `json:"profitTargetNetPct"`   // return to each target after buy and sell fees
+	BreakEven            float64       `json:"breakEven"`            // exit price that covers both fees
+	TechnicalSummary     string        `json:"technicalSummary"`
+	MarketSentiment      string        `json:"marketSentiment"`
+	VolumeAnalysis       string        `json:"volumeAnalysis"`
+	PriceAction          string        `json:"priceAction"`
+	TimeFrame            string        `json:"timeFrame"`
+	LastUpdate           time.Time     `json:"lastUpdate"`
+
+	Indicators     TechnicalIndicators `json:"indicators"`
+	Trend          string              `json:"trend"` // "ขาขึ้น", "ขาลง", "ไซด์เวย์"
+	Support        float64             `json:"support"`
+	Resistance     float64             `json:"resistance"`
+	AlignmentScore float64             `json:"alignmentScore"` // percent of timeframes that support entering
+	Timeframes     []TimeframeView     `json:"timeframes"`
+	Windows        AnalysisWindows     `json:"windows"`                 // candle windows, scaled down for short histories
+	Intraday       bool                `json:"intraday"`                // base timeframe is finer than 1d
+	Recovery       *RecoveryProfile    `json:"recovery,omitempty"`      // post-listing dump, base and bounce
+	VolumeProfile  *VolumeProfile      `json:"volumeProfile,omitempty"` // POC, value area and top volume nodes since listing
+}
+
+// TimeframeView represents one timeframe's reading in a multi-timeframe analysis
+type TimeframeView struct {
+	Interval          string  `json:"interval"`
+	Candles           int     `json:"candles"`
+	Trend             string  `json:"trend"`
+	Support           float64 `json:"support"`
+	Resistance        float64 `json:"resistance"`
+	RSI               float64 `json:"rsi"`
+	ShouldAccumulate  bool    `json:"shouldAccumulate"`
+	ReverseSignal     bool    `json:"reverseSignal"`
+	RecommendedAction string  `json:"recommendedAction"`
+}
+
+// TechnicalIndicators represents the latest indicator values for an analysis
+type TechnicalIndicators struct {
+	Config            IndicatorConfig `json:"config"`
+	SMAShort          float64         `json:"smaShort"`
+	SMALong           float64         `json:"smaLong"`
+	EMAShort          float64         `json:"emaShort"`
+	EMALong           float64         `json:"emaLong"`
+	RSI               float64         `json:"rsi"`
+	MACD              float64         `json:"macd"`
+	MACDSignal        float64         `json:"macdSignal"`
+	MACDHistogram     float64         `json:"macdHistogram"`
+	BollingerUpper    float64         `json:"bollingerUpper"`
+	BollingerMiddle   float64         `json:"bollingerMiddle"`
+	BollingerLower    float64         `json:"bollingerLower"`
+	BollingerPercentB float64         `json:"bollingerPercentB"` // 0 = lower band, 1 = upper band
+	ATR               float64         `json:"atr"`
+	ATRPercent        float64         `json:"atrPercent"` // ATR as percent of price
+	OBV               float64         `json:"obv"`
+	OBVRising         bool            `json:"obvRising"` // OBV higher than SMA_SHORT candles ago
+	StochRSIK         float64         `json:"stochRsiK"`
+	StochRSID         float64         `json:"stochRsiD"`
+	Overbought        bool            `json:"overbought"` // RSI ≥ 70 and stochastic RSI ≥ 80
+	Oversold          bool            `json:"oversold"`   // RSI ≤ 30 or stochastic RSI ≤ 20
+	MomentumUp        bool            `json:"momentumUp"` // MACD histogram > 0 and short EMA above long EMA
 }
 
 // GridConfig represents grid trading configuration
@@ -90,6 +154,7 @@ type GridConfig struct {
 	LowerPrice float64
 	UpperPrice float64
 	Investment float64
+	Mode       string // "arithmetic" or "geometric"
 	CreatedAt  time.Time
 }
 
@@ -127,12 +192,83 @@ type ExchangeInfo struct {
 
 // SymbolInfo represents symbol information including listing date
 type SymbolInfo struct {
-	Symbol               string `json:"symbol"`
-	Status               string `json:"status"`
-	BaseAsset            string `json:"baseAsset"`
-	BaseAssetPrecision   int    `json:"baseAssetPrecision"`
-	QuoteAsset           string `json:"quoteAsset"`
-	QuoteAssetPrecision  int    `json:"quoteAssetPrecision"`
-	OnboardDate          int64  `json:"onboardDate"`
-	IsSpotTradingAllowed bool   `json:"isSpotTradingAllowed"`
+	Symbol               string         `json:"symbol"`
+	Status               string         `json:"status"`
+	BaseAsset            string         `json:"baseAsset"`
+	BaseAssetPrecision   int            `json:"baseAssetPrecision"`
+	QuoteAsset           string         `json:"quoteAsset"`
+	QuoteAssetPrecision  int            `json:"quoteAssetPrecision"`
+	OnboardDate          int64          `json:"onboardDate"`
+	IsSpotTradingAllowed bool           `json:"isSpotTradingAllowed"`
+	Filters              []SymbolFilter `json:"filters"`
+}
+
+// SymbolFilter represents one trading rule of a symbol (PRICE_FILTER, LOT_SIZE, ...)
+type SymbolFilter struct {
+	FilterType  string `json:"filterType"`
+	TickSize    string `json:"tickSize"`
+	StepSize    string `json:"stepSize"`
+	MinQty      string `json:"minQty"`
+	MinNotional string `json:"minNotional"`
+}
+
+// SymbolRules holds the parsed price and quantity rules for placing orders
+type SymbolRules struct {
+	Symbol       string
+	TickSize     float64
+	StepSize     float64
+	MinQty       float64
+	MinNotional  float64
+	tickDecimals int
+	stepDecimals int
+}
+
+// BookLevel represents one price level of the order book
+type BookLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// OrderBook represents a depth snapshot, best prices first
+type OrderBook struct {
+	Bids []BookLevel
+	Asks []BookLevel
+}
+
+// AssetBalance represents the free and locked amount of one asset
+type AssetBalance struct {
+	Asset  string
+	Free   float64
+	Locked float64
+}
+
+// OrderStatus represents an order as returned by the order query endpoints
+type OrderStatus struct {
+	Symbol              string `json:"symbol"`
+	OrderID             int64  `json:"orderId"`
+	ClientOrderID       string `json:"clientOrderId"`
+	Price               string `json:"price"`
+	OrigQty             string `json:"origQty"`
+	ExecutedQty         string `json:"executedQty"`
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+	Status              string `json:"status"`
+	Type                string `json:"type"`
+	Side                string `json:"side"`
+	Time                int64  `json:"time"`
+	UpdateTime          int64  `json:"updateTime"`
+}
+
+// Trade represents one of the account's own fills from /api/v3/myTrades
+type Trade struct {
+	Symbol          string `json:"symbol"`
+	ID              int64  `json:"id"`
+	OrderID         int64  `json:"orderId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	IsBuyer         bool   `json:"isBuyer"`
+	IsMaker         bool   `json:"isMaker"`
 }
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	userStreamURL        = "wss://stream.binance.com:9443/ws/"
+	userStreamTestnetURL = "wss://stream.testnet.binance.vision/ws/"
+	// listenKeys expire after 60 minutes without a keepalive
+	listenKeyKeepAlive = 30 * time.Minute
+	// Binance pings every few minutes, so a silent connection this long is dead
+	userStreamReadTimeout = 10 * time.Minute
+	userStreamMaxBackoff  = time.Minute
+)
+
+// FillEvent represents a trade on one of the account's orders
+type FillEvent struct {
+	Symbol          string    `json:"symbol"`
+	OrderID         int64     `json:"orderId"`
+	ClientOrderID   string    `json:"clientOrderId"`
+	Side            string    `json:"side"`
+	OrderType       string    `json:"orderType"`
+	OrderStatus     string    `json:"orderStatus"` // empty for fills recovered over REST
+	TradeID         int64     `json:"tradeId"`
+	Price           float64   `json:"price"`
+	Quantity        float64   `json:"quantity"`
+	CumulativeQty   float64   `json:"cumulativeQty"`
+	Commission      float64   `json:"commission"`
+	CommissionAsset string    `json:"commissionAsset"`
+	Time            time.Time `json:"time"`
+	Source          string    `json:"source"` // "stream" or "rest"
+}
+
+// BalanceUpdate represents the new free/locked amount of an asset
+type BalanceUpdate struct {
+	Asset  string    `json:"asset"`
+	Free   float64   `json:"free"`
+	Locked float64   `json:"locked"`
+	Time   time.Time `json:"time"`
+}
+
+// UserDataStream delivers the account's fills and balance changes in real time
+type UserDataStream struct {
+	// Fills receives every trade; a slow reader holds back the stream, so keep reading
+	Fills chan FillEvent
+	// Balances keeps only what fits in its buffer; REST is the fallback for a full picture
+	Balances chan BalanceUpdate
+
+	client *BinanceClient
+
+	mu          sync.Mutex
+	conn        *wsConn
+	symbols     map[string]bool  // symbols re-queried over REST after a reconnect
+	lastTradeID map[string]int64 // newest trade delivered per symbol, for de-duplication
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newUserDataStream creates a stream; call Watch for each symbol whose fills must survive reconnects
+func newUserDataStream(client *BinanceClient) *UserDataStream {
+	return &UserDataStream{
+		Fills:       make(chan FillEvent, 256),
+		Balances:    make(chan BalanceUpdate, 256),
+		client:      client,
+		symbols:     map[string]bool{},
+		lastTradeID: map[string]int64{},
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Watch adds a symbol to the REST gap fill after reconnects
+func (s *UserDataStream) Watch(symbol string) {
+	s.mu.Lock()
+	s.symbols[symbol] = true
+	s.mu.Unlock()
+}
+
+// Start connects in the background and keeps reconnecting until Close
+func (s *UserDataStream) Start() {
+	go s.run()
+}
+
+// Close stops the stream and waits for it to finish
+func (s *UserDataStream) Close() {
+	close(s.stop)
+	s.mu.Lock()
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.mu.Unlock()
+	<-s.done
+}
+
+// stopped reports whether Close has been called
+func (s *UserDataStream) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Create a listenKey for the user data stream
+func createListenKey(client *BinanceClient) (string, error) {
+	body, err := apiKeyRequest(client, "POST", "/api/v3/userDataStream", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", err
+	}
+	return response.ListenKey, nil
+}
+
+// Extend a listenKey's validity by 60 minutes
+func keepAliveListenKey(client *BinanceClient, listenKey string) error {
+	params := url.Values{}
+	params.Set("listenKey", listenKey)
+	_, err := apiKeyRequest(client, "PUT", "/api/v3/userDataStream", params)
+	return err
+}
+
+// run is the connect/read/reconnect loop
+func (s *UserDataStream) run() {
+	defer close(s.done)
+
+	backoff := time.Second
+	var disconnectedAt time.Time
+
+	for !s.stopped() {
+		err := s.connectAndRead(&disconnectedAt)
+		if s.stopped() {
+			return
+		}
+
+		if disconnectedAt.IsZero() {
+			disconnectedAt = time.Now()
+		}
+		fmt.Printf("⚠️ user data stream หลุด: %v - เชื่อมต่อใหม่ใน %v\n", err, backoff)
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > userStreamMaxBackoff {
+			backoff = userStreamMaxBackoff
+		}
+	}
+}
+
+// connectAndRead runs one connection until it fails
+func (s *UserDataStream) connectAndRead(disconnectedAt *time.Time) error {
+	listenKey, err := createListenKey(s.client)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถสร้าง listenKey: %v", err)
+	}
+
+	conn, err := dialWebSocket(userStreamBase() + listenKey)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Close may have run while connecting; it only closes the connection it can see
+	s.mu.Lock()
+	s.conn = conn
+	closing := s.stopped()
+	s.mu.Unlock()
+	if closing {
+		return nil
+	}
+
+	// Anything that happened while disconnected is recovered over REST
+	if !disconnectedAt.IsZero() {
+		s.fillGaps(*disconnectedAt)
+		*disconnectedAt = time.Time{}
+	}
+	fmt.Println("🔌 เชื่อมต่อ user data stream แล้ว")
+
+	keepAliveDone := make(chan struct{})
+	defer close(keepAliveDone)
+	go func() {
+		ticker := time.NewTicker(listenKeyKeepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-keepAliveDone:
+				return
+			case <-ticker.C:
+				if err := keepAliveListenKey(s.client, listenKey); err != nil {
+					fmt.Printf("⚠️ ต่ออายุ listenKey ล้มเหลว: %v\n", err)
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(userStreamReadTimeout))
+		message, err := conn.ReadMessage()
+		if err != nil {
+			if s.stopped() {
+				return nil
+			}
+			*disconnectedAt = time.Now()
+			return err
+		}
+		if expired := s.handleMessage(message); expired {
+			*disconnectedAt = time.Now()
+			return fmt.Errorf("listenKey หมดอายุ")
+		}
+	}
+}
+
+// handleMessage decodes one event; it reports true when the listenKey has expired
+func (s *UserDataStream) handleMessage(message []byte) bool {
+	// Event keys differ only by case ("c"/"C", "l"/"L"), so decode into an exact-key map
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(message, &event); err != nil {
+		return false
+	}
+
+	switch rawString(event, "e") {
+	case "executionReport":
+		if rawString(event, "x") != "TRADE" {
+			return false
+		}
+		s.deliverFill(FillEvent{
+			Symbol:          rawString(event, "s"),
+			OrderID:         rawInt(event, "i"),
+			ClientOrderID:   rawString(event, "c"),
+			Side:            rawString(event, "S"),
+			OrderType:       rawString(event, "o"),
+			OrderStatus:     rawString(event, "X"),
+			TradeID:         rawInt(event, "t"),
+			Price:           rawFloat(event, "L"),
+			Quantity:        rawFloat(event, "l"),
+			CumulativeQty:   rawFloat(event, "z"),
+			Commission:      rawFloat(event, "n"),
+			CommissionAsset: rawString(event, "N"),
+			Time:            time.UnixMilli(rawInt(event, "T")),
+			Source:          "stream",
+		})
+
+	case "outboundAccountPosition":
+		var balances []struct {
+			Asset  string `json:"a"`
+			Free   string `json:"f"`
+			Locked string `json:"l"`
+		}
+		json.Unmarshal(event["B"], &balances)
+		eventTime := time.UnixMilli(rawInt(event, "E"))
+		for _, balance := range balances {
+			free, _ := strconv.ParseFloat(balance.Free, 64)
+			locked, _ := strconv.ParseFloat(balance.Locked, 64)
+			s.deliverBalance(BalanceUpdate{Asset: balance.Asset, Free: free, Locked: locked, Time: eventTime})
+		}
+
+	case "listenKeyExpired":
+		return true
+	}
+
+	return false
+}
+
+// deliverFill sends a fill once, skipping trades already delivered
+func (s *UserDataStream) deliverFill(fill FillEvent) {
+	s.mu.Lock()
+	if fill.TradeID <= s.lastTradeID[fill.Symbol] {
+		s.mu.Unlock()
+		return
+	}
+	s.lastTradeID[fill.Symbol] = fill.TradeID
+	s.mu.Unlock()
+
+	select {
+	case s.Fills <- fill:
+	case <-s.stop:
+	}
+}
+
+// deliverBalance sends a balance update without ever blocking the stream
+func (s *UserDataStream) deliverBalance(update BalanceUpdate) {
+	select {
+	case s.Balances <- update:
+	default:
+	}
+}
+
+// fillGaps re-queries trades and balances missed while the stream was down
+func (s *UserDataStream) fillGaps(since time.Time) {
+	s.mu.Lock()
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	s.mu.Unlock()
+
+	// Also cover every symbol with an order the journal still considers open
+	if journal, err := loadJournal(); err == nil {
+		for _, entry := range journal.Entries {
+			if isOpenJournalStatus(entry.Status) && !s.isWatched(entry.Symbol) {
+				symbols = append(symbols, entry.Symbol)
+			}
+		}
+	}
+
+	recovered := 0
+	for _, symbol := range symbols {
+		trades, err := getMyTradesSince(s.client, symbol, since.Add(-time.Minute))
+		if err != nil {
+			fmt.Printf("⚠️ ไม่สามารถดึงเทรดที่พลาดของ %s: %v\n", symbol, err)
+			continue
+		}
+		for _, trade := range trades {
+			price, _ := strconv.ParseFloat(trade.Price, 64)
+			qty, _ := strconv.ParseFloat(trade.Qty, 64)
+			commission, _ := strconv.ParseFloat(trade.Commission, 64)
+			side := "SELL"
+			if trade.IsBuyer {
+				side = "BUY"
+			}
+			s.deliverFill(FillEvent{
+				Symbol:          trade.Symbol,
+				OrderID:         trade.OrderID,
+				Side:            side,
+				TradeID:         trade.ID,
+				Price:           price,
+				Quantity:        qty,
+				Commission:      commission,
+				CommissionAsset: trade.CommissionAsset,
+				Time:            time.UnixMilli(trade.Time),
+				Source:          "rest",
+			})
+			recovered++
+		}
+	}
+
+	if balances, err := getAccountBalances(s.client); err == nil {
+		for _, balance := range balances {
+			s.deliverBalance(BalanceUpdate{Asset: balance.Asset, Free: balance.Free, Locked: balance.Locked, Time: time.Now()})
+		}
+	}
+
+	if recovered > 0 {
+		fmt.Printf("🩹 กู้คืน %d เทรดที่เกิดขึ้นระหว่างหลุดการเชื่อมต่อ\n", recovered)
+	}
+}
+
+// isWatched reports whether a symbol is part of the REST gap fill
+func (s *UserDataStream) isWatched(symbol string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.symbols[symbol]
+}
+
+// rawString returns an event field as a string, empty when missing
+func rawString(event map[string]json.RawMessage, key string) string {
+	var value string
+	json.Unmarshal(event[key], &value)
+	return value
+}
+
+// rawFloat returns a string-encoded event field as a float, 0 when missing
+func rawFloat(event map[string]json.RawMessage, key string) float64 {
+	value, _ := strconv.ParseFloat(rawString(event, key), 64)
+	return value
+}
+
+// rawInt returns a numeric event field as an int64, 0 when missing
+func rawInt(event map[string]json.RawMessage, key string) int64 {
+	var value int64
+	json.Unmarshal(event[key], &value)
+	return value
+}
+
+// runStreamCommand handles: stream [SYMBOL...] - prints fills and balance changes live
+func runStreamCommand(args []string) error {
+	client, err := newClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	stream := newUserDataStream(client)
+	for _, symbol := range args {
+		stream.Watch(strings.ToUpper(symbol))
+	}
+	stream.Start()
+	defer stream.Close()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	fmt.Println("📡 กำลังฟัง fills และยอดเงินแบบเรียลไทม์ (Ctrl+C เพื่อหยุด)")
+	for {
+		select {
+		case fill := <-stream.Fills:
+			fmt.Printf("✅ %s %s %s @ $%s (order %d, %s)\n", fill.Symbol, fill.Side,
+				strconv.FormatFloat(fill.Quantity, 'f', -1, 64), strconv.FormatFloat(fill.Price, 'f', -1, 64),
+				fill.OrderID, fill.Source)
+		case update := <-stream.Balances:
+			fmt.Printf("💰 %s: ว่าง %s, ล็อก %s\n", update.Asset,
+				strconv.FormatFloat(update.Free, 'f', -1, 64), strconv.FormatFloat(update.Locked, 'f', -1, 64))
+		case <-stop:
+			fmt.Println("\n🔌 ปิด user data stream")
+			return nil
+		}
+	}
+}
+
+// userStreamBase returns the websocket base matching binanceBaseURL
+func userStreamBase() string {
+	if useTestnet() {
+		return userStreamTestnetURL
+	}
+	return userStreamURL
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Level kinds in a volume profile
+const (
+	levelPOC = "poc" // point of control: the price with the most volume
+	levelHVN = "hvn" // high-volume node: price accepted, acts as support/resistance
+	levelLVN = "lvn" // low-volume node: price rejected, moves through quickly
+)
+
+const (
+	// Bins on each side a node must dominate
+	profileNodeReach = 2
+	// Node thresholds relative to the average bin volume
+	hvnMinRatio = 1.2
+	lvnMaxRatio = 0.5
+)
+
+// VolumeProfileConfig represents the volume profile settings loaded from .env
+type VolumeProfileConfig struct {
+	Bins      int     // PROFILE_BINS: price buckets between the lowest low and highest high
+	ValueArea float64 // PROFILE_VALUE_AREA: percent of volume inside the value area
+	TopLevels int     // PROFILE_TOP_LEVELS: nodes of each kind kept in the output
+}
+
+// ProfileLevel represents one price bucket of the profile
+type ProfileLevel struct {
+	Price    float64 `json:"price"` // bucket middle
+	Low      float64 `json:"low"`
+	High     float64 `json:"high"`
+	Kind     string  `json:"kind"`     // poc, hvn or lvn
+	Strength float64 `json:"strength"` // bucket volume relative to the POC (0-1)
+}
+
+// VolumeProfile represents volume traded at each price since listing
+type VolumeProfile struct {
+	Interval        string         `json:"interval"`
+	Candles         int            `json:"candles"`
+	BinSize         float64        `json:"binSize"`
+	POC             ProfileLevel   `json:"poc"`
+	ValueAreaHigh   float64        `json:"valueAreaHigh"`
+	ValueAreaLow    float64        `json:"valueAreaLow"`
+	HighVolumeNodes []ProfileLevel `json:"highVolumeNodes"` // strongest first
+	LowVolumeNodes  []ProfileLevel `json:"lowVolumeNodes"`  // thinnest first
+}
+
+// loadVolumeProfileConfig reads the bucket count, value area and node count from .env
+func loadVolumeProfileConfig() VolumeProfileConfig {
+	return VolumeProfileConfig{
+		Bins:      max(10, envInt("PROFILE_BINS", 50)),
+		ValueArea: envFloat("PROFILE_VALUE_AREA", 70),
+		TopLevels: max(1, envInt("PROFILE_TOP_LEVELS", 5)),
+	}
+}
+
+// buildVolumeProfile spreads each candle's volume evenly over its high-low range and
+// finds the POC, value area and volume nodes
+func buildVolumeProfile(klines []Kline, interval string, config VolumeProfileConfig) *VolumeProfile {
+	if len(klines) == 0 {
+		return nil
+	}
+
+	lowest, highest := klines[0].Low, klines[0].High
+	for _, k := range klines {
+		lowest = math.Min(lowest, k.Low)
+		highest = math.Max(highest, k.High)
+	}
+	if lowest <= 0 || highest <= lowest {
+		return nil
+	}
+
+	binSize := (highest - lowest) / float64(config.Bins)
+	bin := func(price float64) int {
+		return min(config.Bins-1, max(0, int((price-lowest)/binSize)))
+	}
+
+	volumes := make([]float64, config.Bins)
+	total := 0.0
+	for _, k := range klines {
+		if k.Volume <= 0 {
+			continue
+		}
+		total += k.Volume
+		first, last := bin(k.Low), bin(k.High)
+		if first == last || k.High <= k.Low {
+			volumes[first] += k.Volume
+			continue
+		}
+		for i := first; i <= last; i++ {
+			binLow := lowest + float64(i)*binSize
+			overlap := math.Min(k.High, binLow+binSize) - math.Max(k.Low, binLow)
+			volumes[i] += k.Volume * math.Max(overlap, 0) / (k.High - k.Low)
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	level := func(i int, kind string, peak float64) ProfileLevel {
+		low := lowest + float64(i)*binSize
+		return ProfileLevel{Price: low + binSize/2, Low: low, High: low + binSize, Kind: kind, Strength: volumes[i] / peak}
+	}
+
+	poc := 0
+	for i := range volumes {
+		if volumes[i] > volumes[poc] {
+			poc = i
+		}
+	}
+	peak := volumes[poc]
+
+	// Value area: grow from the POC towards the heavier neighbor until it holds ValueArea percent
+	lo, hi := poc, poc
+	inside := volumes[poc]
+	for inside < total*config.ValueArea/100 && (lo > 0 || hi < config.Bins-1) {
+		below, above := -1.0, -1.0
+		if lo > 0 {
+			below = volumes[lo-1]
+		}
+		if hi < config.Bins-1 {
+			above = volumes[hi+1]
+		}
+		if above >= below {
+			hi++
+			inside += above
+		} else {
+			lo--
+			inside += below
+		}
+	}
+
+	profile := &VolumeProfile{
+		Interval:      interval,
+		Candles:       len(klines),
+		BinSize:       binSize,
+		POC:           level(poc, levelPOC, peak),
+		ValueAreaHigh: lowest + float64(hi+1)*binSize,
+		ValueAreaLow:  lowest + float64(lo)*binSize,
+	}
+
+	// Nodes: buckets that dominate (or are dominated by) their neighbors
+	average := total / float64(config.Bins)
+	for i := range volumes {
+		if i == poc {
+			continue
+		}
+		isPeak, isTrough := true, i >= profileNodeReach && i < config.Bins-profileNodeReach
+		for j := max(0, i-profileNodeReach); j <= min(config.Bins-1, i+profileNodeReach); j++ {
+			if j == i {
+				continue
+			}
+			// Ties go to the lower bucket so a flat top yields one node
+			if volumes[j] > volumes[i] || (j < i && volumes[j] == volumes[i]) {
+				isPeak = false
+			}
+			if volumes[j] < volumes[i] || (j < i && volumes[j] == volumes[i]) {
+				isTrough = false
+			}
+		}
+		if isPeak && volumes[i] >= average*hvnMinRatio {
+			profile.HighVolumeNodes = append(profile.HighVolumeNodes, level(i, levelHVN, peak))
+		} else if isTrough && volumes[i] <= average*lvnMaxRatio {
+			profile.LowVolumeNodes = append(profile.LowVolumeNodes, level(i, levelLVN, peak))
+		}
+	}
+
+	sort.Slice(profile.HighVolumeNodes, func(i, j int) bool {
+		return profile.HighVolumeNodes[i].Strength > profile.HighVolumeNodes[j].Strength
+	})
+	sort.Slice(profile.LowVolumeNodes, func(i, j int) bool {
+		return profile.LowVolumeNodes[i].Strength < profile.LowVolumeNodes[j].Strength
+	})
+	if len(profile.HighVolumeNodes) > config.TopLevels {
+		profile.HighVolumeNodes = profile.HighVolumeNodes[:config.TopLevels]
+	}
+	if len(profile.LowVolumeNodes) > config.TopLevels {
+		profile.LowVolumeNodes = profile.LowVolumeNodes[:config.TopLevels]
+	}
+	return profile
+}
+
+// acceptedLevels returns the POC and high-volume nodes, the prices the market traded most at
+func (p *VolumeProfile) acceptedLevels() []ProfileLevel {
+	return append([]ProfileLevel{p.POC}, p.HighVolumeNodes...)
+}
+
+// supportsBelow returns profile prices under price: accepted levels and the value area low
+func (p *VolumeProfile) supportsBelow(price float64) []float64 {
+	var supports []float64
+	for _, level := range p.acceptedLevels() {
+		if level.Price < price {
+			supports = append(supports, level.Price)
+		}
+	}
+	if p.ValueAreaLow < price {
+		supports = append(supports, p.ValueAreaLow)
+	}
+	return supports
+}
+
+// resistancesAbove returns profile prices over price: accepted levels and the value area high
+func (p *VolumeProfile) resistancesAbove(price float64) []float64 {
+	var resistances []float64
+	for _, level := range p.acceptedLevels() {
+		if level.Price > price {
+			resistances = append(resistances, level.Price)
+		}
+	}
+	if p.ValueAreaHigh > price {
+		resistances = append(resistances, p.ValueAreaHigh)
+	}
+	return resistances
+}
+
+// accumulationZone returns the accepted level holding price, or the nearest one below it
+func (p *VolumeProfile) accumulationZone(price float64) (ProfileLevel, bool) {
+	var zone ProfileLevel
+	found := false
+	for _, level := range p.acceptedLevels() {
+		if level.Low <= price && (!found || level.Low > zone.Low) {
+			zone, found = level, true
+		}
+	}
+	return zone, found
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// binKlines puts each volume into its own 1-wide bucket between 10 and 20, with a
+// zero-volume candle spanning the whole range
+func binKlines(volumes []float64) []Kline {
+	klines := []Kline{{Low: 10, High: 20}}
+	for i, volume := range volumes {
+		price := 10.5 + float64(i)
+		klines = append(klines, Kline{Low: price, High: price, Close: price, Volume: volume})
+	}
+	return klines
+}
+
+func TestBuildVolumeProfile(t *testing.T) {
+	config := VolumeProfileConfig{Bins: 10, ValueArea: 70, TopLevels: 5}
+
+	tests := []struct {
+		name    string
+		klines  []Kline
+		wantPOC ProfileLevel
+		wantVAL float64
+		wantVAH float64
+		wantHVN []float64
+		wantLVN []float64
+	}{
+		{
+			name:    "nodes and value area",
+			klines:  binKlines([]float64{2, 2, 10, 2, 0.5, 2, 6, 2, 2, 2}),
+			wantPOC: ProfileLevel{Price: 12.5, Low: 12, High: 13, Kind: levelPOC, Strength: 1},
+			wantVAL: 10,
+			wantVAH: 17,
+			wantHVN: []float64{16.5},
+			wantLVN: []float64{14.5},
+		},
+		{
+			name:    "volume spreads over the candle range",
+			klines:  []Kline{{Low: 10, High: 20, Volume: 10}, {Low: 10, High: 12, Volume: 4}},
+			wantPOC: ProfileLevel{Price: 10.5, Low: 10, High: 11, Kind: levelPOC, Strength: 1},
+			wantVAL: 10,
+			wantVAH: 16,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := buildVolumeProfile(tt.klines, "1h", config)
+			if profile == nil {
+				t.Fatal("profile = nil")
+			}
+			if profile.Candles != len(tt.klines) || profile.Interval != "1h" {
+				t.Errorf("candles/interval = %d/%s, want %d/1h", profile.Candles, profile.Interval, len(tt.klines))
+			}
+			poc := profile.POC
+			if poc.Kind != tt.wantPOC.Kind || math.Abs(poc.Price-tt.wantPOC.Price) > 1e-9 || math.Abs(poc.Low-tt.wantPOC.Low) > 1e-9 ||
+				math.Abs(poc.High-tt.wantPOC.High) > 1e-9 || math.Abs(poc.Strength-tt.wantPOC.Strength) > 1e-9 {
+				t.Errorf("POC = %+v, want %+v", poc, tt.wantPOC)
+			}
+			if math.Abs(profile.ValueAreaLow-tt.wantVAL) > 1e-9 || math.Abs(profile.ValueAreaHigh-tt.wantVAH) > 1e-9 {
+				t.Errorf("value area = %v-%v, want %v-%v", profile.ValueAreaLow, profile.ValueAreaHigh, tt.wantVAL, tt.wantVAH)
+			}
+			if got := levelPrices(profile.HighVolumeNodes); !floatsEqual(got, tt.wantHVN) {
+				t.Errorf("high-volume nodes = %v, want %v", got, tt.wantHVN)
+			}
+			if got := levelPrices(profile.LowVolumeNodes); !floatsEqual(got, tt.wantLVN) {
+				t.Errorf("low-volume nodes = %v, want %v", got, tt.wantLVN)
+			}
+		})
+	}
+
+	empty := []struct {
+		name   string
+		klines []Kline
+	}{
+		{"no klines", nil},
+		{"no volume", []Kline{{Low: 10, High: 20}}},
+		{"flat price", []Kline{{Low: 10, High: 10, Volume: 5}}},
+	}
+	for _, tt := range empty {
+		t.Run(tt.name, func(t *testing.T) {
+			if profile := buildVolumeProfile(tt.klines, "1h", config); profile != nil {
+				t.Errorf("profile = %+v, want nil", profile)
+			}
+		})
+	}
+}
+
+func TestVolumeProfileLevels(t *testing.T) {
+	profile := buildVolumeProfile(binKlines([]float64{2, 2, 10, 2, 0.5, 2, 6, 2, 2, 2}), "1h", VolumeProfileConfig{Bins: 10, ValueArea: 70, TopLevels: 5})
+
+	if got := profile.supportsBelow(15); !floatsEqual(got, []float64{12.5, 10}) {
+		t.Errorf("supportsBelow(15) = %v, want [12.5 10]", got)
+	}
+	if got := profile.resistancesAbove(15); !floatsEqual(got, []float64{16.5, 17}) {
+		t.Errorf("resistancesAbove(15) = %v, want [16.5 17]", got)
+	}
+
+	zones := []struct {
+		price float64
+		want  float64
+		found bool
+	}{
+		{15, 12.5, true},
+		{16.2, 16.5, true},
+		{11, 0, false},
+	}
+	for _, z := range zones {
+		zone, found := profile.accumulationZone(z.price)
+		if found != z.found || math.Abs(zone.Price-z.want) > 1e-9 {
+			t.Errorf("accumulationZone(%v) = %v/%v, want %v/%v", z.price, zone.Price, found, z.want, z.found)
+		}
+	}
+}
+
+// levelPrices returns the prices of profile levels in order
+func levelPrices(levels []ProfileLevel) []float64 {
+	var prices []float64
+	for _, level := range levels {
+		prices = append(prices, level.Price)
+	}
+	return prices
+}